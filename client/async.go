@@ -0,0 +1,517 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/boxtsecond/gosmpp/pkg"
+)
+
+var (
+	ErrResponseTimeout = errors.New("smpp client: response timeout")
+	ErrNotConnected    = errors.New("smpp client: not connected")
+	ErrClientClosed    = errors.New("smpp client: closed")
+	ErrUnbound         = errors.New("smpp client: unbound by peer")
+)
+
+const (
+	DefaultWindow          = 10               // 默认最多同时等待响应的请求数
+	DefaultResponseTimeout = 10 * time.Second // 默认 response_timer
+)
+
+// 处理 SMSC 发来的请求, 如 deliver_sm; 返回的响应以请求的序列号发送, 为 nil 时不发送
+type Handler func(req pkg.Packer) pkg.Packer
+
+// 设置窗口大小与 response_timer, size 不大于 0 时使用 DefaultWindow, timeout 不大于 0 时使用 DefaultResponseTimeout
+func WithWindow(size int, timeout time.Duration) Option {
+	return func(cli *Client) {
+		cli.windowSize = size
+		cli.responseTimeout = timeout
+	}
+}
+
+// 设置 SMSC 发来的请求的处理函数, 每个请求在单独的协程中处理, 设置后不再使用 WithInbound 的队列
+// 未设置 Handler 时请求放入队列, 由调用方通过 RecvAndUnpackPkt 读取并响应
+// enquire_link 与 unbind 总是由客户端自动响应
+func WithHandler(h Handler) Option {
+	return func(cli *Client) {
+		cli.handler = h
+	}
+}
+
+// 未设置 Handler 时 SMSC 发来的请求放入长度为 size 的队列, 通过 RecvAndUnpackPkt 读取并自行响应
+// 队列已满时 deliver_sm 与 data_sm 以 ESME_RMSGQFUL 拒绝, SMSC 稍后重发; 默认长度与 size 不大于 0 时为 DefaultWindow
+func WithInbound(size int) Option {
+	return func(cli *Client) {
+		if size <= 0 {
+			size = DefaultWindow
+		}
+		cli.inboundSize = size
+	}
+}
+
+// 设置丢弃 PDU 等情况的日志, 默认输出到标准错误
+func WithErrorLog(l *log.Logger) Option {
+	return func(cli *Client) {
+		cli.errorLog = l
+	}
+}
+
+// 一个等待响应的请求
+type Future struct {
+	SequenceNum uint32
+	Request     pkg.Packer
+
+	once  sync.Once
+	done  chan struct{}
+	resp  pkg.Packer
+	err   error
+	cb    func(resp pkg.Packer, err error)
+	timer *time.Timer
+}
+
+func (f *Future) complete(resp pkg.Packer, err error) bool {
+	completed := false
+	f.once.Do(func() {
+		completed = true
+		if f.timer != nil {
+			f.timer.Stop()
+		}
+		f.resp, f.err = resp, err
+		close(f.done)
+		if f.cb != nil {
+			// 不在读取协程中回调, 以免阻塞读取; 回调中可以再发送请求并等待响应
+			go f.cb(resp, err)
+		}
+	})
+	return completed
+}
+
+// 收到响应、超时或连接断开时关闭
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// 阻塞直到完成, 返回匹配的响应; 超时返回 ErrResponseTimeout
+func (f *Future) Result() (pkg.Packer, error) {
+	<-f.done
+	return f.resp, f.err
+}
+
+// 同 Result, ctx 结束时返回 ctx.Err(), 请求仍在窗口中直到收到响应或超时
+func (f *Future) Wait(ctx context.Context) (pkg.Packer, error) {
+	select {
+	case <-f.done:
+		return f.resp, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// 一个连接上等待响应的请求, 按序列号匹配
+type window struct {
+	conn    *pkg.Conn
+	timeout time.Duration
+	slots   chan struct{}
+	inbound chan pkg.Packer
+
+	mu      sync.Mutex
+	pending map[uint32]*Future
+	closed  chan struct{}
+	idle    chan struct{} // 没有等待响应的请求时关闭, 由 drain 创建
+	closing bool          // 已开始 drain, 除 unbind 外不再发送新的请求
+	err     error
+	replies []pkg.Packer  // SendReqPkt 发送的请求收到的响应, 由 RecvAndUnpackPkt 读取
+	replied chan struct{} // 有新的响应时写入
+
+	lastRead int64 // 最近一次收到 PDU 的时间, UnixNano
+	missed   int32 // 之后发送的 enquire_link 数
+}
+
+func newWindow(conn *pkg.Conn, size int, timeout time.Duration, inbound int) *window {
+	if size <= 0 {
+		size = DefaultWindow
+	}
+	var queue chan pkg.Packer
+	if inbound > 0 {
+		queue = make(chan pkg.Packer, inbound)
+	}
+	if timeout <= 0 {
+		timeout = DefaultResponseTimeout
+	}
+	return &window{
+		conn:    conn,
+		timeout: timeout,
+		slots:   make(chan struct{}, size),
+		inbound: queue,
+		pending: make(map[uint32]*Future),
+		closed:  make(chan struct{}),
+		replied: make(chan struct{}, 1),
+
+		lastRead: time.Now().UnixNano(),
+	}
+}
+
+func (w *window) add(f *Future) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	if _, unbind := f.Request.(*pkg.SmppUnbindReqPkt); w.closing && !unbind {
+		return ErrClientClosed
+	}
+	w.pending[f.SequenceNum] = f
+	f.timer = time.AfterFunc(w.timeout, func() {
+		if w.take(f.SequenceNum) == f {
+			f.complete(nil, ErrResponseTimeout)
+		}
+	})
+	return nil
+}
+
+// 取出序列号对应的请求并释放窗口
+func (w *window) take(seq uint32) *Future {
+	w.mu.Lock()
+	f, ok := w.pending[seq]
+	if ok {
+		delete(w.pending, seq)
+		if len(w.pending) == 0 && w.idle != nil {
+			close(w.idle)
+			w.idle = nil
+		}
+	}
+	w.mu.Unlock()
+	if ok {
+		<-w.slots
+	}
+	return f
+}
+
+// 连接断开, 所有等待中的请求以 err 结束
+func (w *window) close(err error) {
+	w.mu.Lock()
+	if w.err != nil {
+		w.mu.Unlock()
+		return
+	}
+	w.err = err
+	pending := w.pending
+	w.pending = make(map[uint32]*Future)
+	close(w.closed)
+	w.mu.Unlock()
+
+	for _, f := range pending {
+		<-w.slots
+		f.complete(nil, err)
+	}
+}
+
+// 停止接受新的请求并等待所有请求收到响应, ctx 结束时返回 ctx.Err(), 连接断开时返回断开原因
+func (w *window) drain(ctx context.Context) error {
+	w.mu.Lock()
+	w.closing = true
+	if w.err != nil {
+		w.mu.Unlock()
+		return w.err
+	}
+	if len(w.pending) == 0 {
+		w.mu.Unlock()
+		return nil
+	}
+	if w.idle == nil {
+		w.idle = make(chan struct{})
+	}
+	idle := w.idle
+	w.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-w.closed:
+		return w.error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// 保存 SendReqPkt 的请求收到的响应
+func (w *window) pushReply(resp pkg.Packer) {
+	w.mu.Lock()
+	w.replies = append(w.replies, resp)
+	w.mu.Unlock()
+	w.notifyReply()
+}
+
+func (w *window) notifyReply() {
+	select {
+	case w.replied <- struct{}{}:
+	default:
+	}
+}
+
+// 取出最早的响应, 没有时返回 nil
+func (w *window) popReply() pkg.Packer {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.replies) == 0 {
+		return nil
+	}
+	p := w.replies[0]
+	w.replies[0] = nil
+	w.replies = w.replies[1:]
+	if len(w.replies) > 0 {
+		w.notifyReply() // 唤醒其他读取的协程
+	}
+	return p
+}
+
+func (w *window) error() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// 响应的序列号
+func responseSeq(p pkg.Packer) (uint32, bool) {
+	switch r := p.(type) {
+	case *pkg.SmppBindTransceiverRespPkt:
+		return r.SequenceNum, true
+	case *pkg.SmppSubmitRespPkt:
+		return r.SequenceNum, true
+	case *pkg.SmppDeliverRespPkt:
+		return r.SequenceNum, true
+	case *pkg.SmppQueryRespPkt:
+		return r.SequenceNum, true
+	case *pkg.SmppDataRespPkt:
+		return r.SequenceNum, true
+	case *pkg.SmppCancelRespPkt:
+		return r.SequenceNum, true
+	case *pkg.SmppEnquireLinkRespPkt:
+		return r.SequenceNum, true
+	case *pkg.SmppUnbindRespPkt:
+		return r.SequenceNum, true
+	case *pkg.SmppGenericNackReqPkt:
+		return r.SequenceNum, true
+	}
+	return 0, false
+}
+
+// 请求的序列号
+func requestSeq(p pkg.Packer) uint32 {
+	switch r := p.(type) {
+	case *pkg.SmppDeliverReqPkt:
+		return r.SequenceNum
+	case *pkg.SmppDataReqPkt:
+		return r.SequenceNum
+	case *pkg.SmppEnquireLinkReqPkt:
+		return r.SequenceNum
+	case *pkg.SmppUnbindReqPkt:
+		return r.SequenceNum
+	case *pkg.SmppSubmitReqPkt:
+		return r.SequenceNum
+	case *pkg.SmppQueryReqPkt:
+		return r.SequenceNum
+	case *pkg.SmppCancelReqPkt:
+		return r.SequenceNum
+	}
+	return 0
+}
+
+// 响应的 command_status
+func responseStatus(p pkg.Packer) (pkg.CommandID, pkg.Status) {
+	switch r := p.(type) {
+	case *pkg.SmppGenericNackReqPkt:
+		return pkg.SMPP_GENERIC_NACK, r.Status
+	case *pkg.SmppBindTransceiverRespPkt:
+		return pkg.SMPP_BIND_TRANSCEIVER_RESP, r.Status
+	case *pkg.SmppSubmitRespPkt:
+		return pkg.SMPP_SUBMIT_RESP, r.Status
+	case *pkg.SmppDataRespPkt:
+		return pkg.SMPP_DATA_RESP, r.Status
+	case *pkg.SmppQueryRespPkt:
+		return pkg.SMPP_QUERY_RESP, r.Status
+	case *pkg.SmppCancelRespPkt:
+		return pkg.SMPP_CANCEL_RESP, r.Status
+	case *pkg.SmppUnbindRespPkt:
+		return pkg.SMPP_UNBIND_RESP, r.Status
+	case *pkg.SmppDeliverRespPkt:
+		return pkg.SMPP_DELIVER_RESP, r.Status
+	}
+	return 0, pkg.ESME_ROK
+}
+
+// submit_sm 与 data_sm 等待限速
+func (cli *Client) wait(ctx context.Context, p pkg.Packer) error {
+	if cli.limiter == nil {
+		return nil
+	}
+	if isMessage(p) {
+		return cli.limiter.Wait(ctx)
+	}
+	return nil
+}
+
+// 读取协程, 响应交给对应的请求, 请求交给 Handler 或放入 RecvAndUnpackPkt 的队列
+func (cli *Client) readLoop(w *window) {
+	if w.inbound != nil {
+		defer close(w.inbound)
+	}
+	for {
+		p, err := w.conn.RecvAndUnpackPkt(0)
+		if err != nil {
+			w.close(err)
+			return
+		}
+		atomic.StoreInt64(&w.lastRead, time.Now().UnixNano())
+		atomic.StoreInt32(&w.missed, 0)
+
+		// 链路检测与退出由客户端处理
+		switch r := p.(type) {
+		case *pkg.SmppEnquireLinkReqPkt:
+			go w.conn.SendPkt(&pkg.SmppEnquireLinkRespPkt{}, r.SequenceNum)
+			continue
+		case *pkg.SmppEnquireLinkRespPkt:
+			if f := w.take(r.SequenceNum); f != nil {
+				f.complete(p, nil)
+			}
+			continue
+		case *pkg.SmppUnbindReqPkt:
+			w.conn.SendPkt(&pkg.SmppUnbindRespPkt{}, r.SequenceNum)
+			w.close(ErrUnbound)
+			w.conn.Conn.Close()
+			return
+		}
+
+		if seq, ok := responseSeq(p); ok {
+			if _, status := responseStatus(p); status == pkg.ESME_RTHROTTLED && cli.limiter != nil {
+				cli.limiter.Throttled()
+			}
+			if f := w.take(seq); f != nil {
+				f.complete(p, nil)
+			} else {
+				// 超时后才到达的响应等, 丢弃
+				cli.errorLog.Printf("drop a response matching no request from %v[%d]\n", w.conn.RemoteAddr(), seq)
+			}
+			continue
+		}
+
+		if cli.handler != nil {
+			go func(req pkg.Packer) {
+				if rsp := cli.handler(req); rsp != nil {
+					w.conn.SendPkt(rsp, requestSeq(req))
+				}
+			}(p)
+			continue
+		}
+		// 队列已满时不阻塞读取, 以 ESME_RMSGQFUL 拒绝让对端稍后重发
+		select {
+		case w.inbound <- p:
+		default:
+			cli.errorLog.Printf("inbound queue is full, reject a request from %v[%d]\n", w.conn.RemoteAddr(), requestSeq(p))
+			go w.conn.SendPkt(rejectResponse(p, pkg.ESME_RMSGQFUL), requestSeq(p))
+		}
+	}
+}
+
+// 队列已满时拒绝请求的响应, deliver_sm 与 data_sm 以 status 响应, 其他请求返回 generic_nack
+func rejectResponse(req pkg.Packer, status pkg.Status) pkg.Packer {
+	switch req.(type) {
+	case *pkg.SmppDeliverReqPkt:
+		return &pkg.SmppDeliverRespPkt{Status: status}
+	case *pkg.SmppDataReqPkt:
+		return &pkg.SmppDataRespPkt{Status: status}
+	}
+	return &pkg.SmppGenericNackReqPkt{Status: pkg.ESME_RINVCMDID}
+}
+
+// 当前连接已启动的窗口, 没有时返回 nil
+func (cli *Client) current() *window {
+	cli.mu.Lock()
+	defer cli.mu.Unlock()
+	if cli.win == nil || cli.win.conn != cli.conn {
+		return nil
+	}
+	return cli.win
+}
+
+// 当前连接的窗口, 读取协程未启动时启动
+func (cli *Client) window() (*window, error) {
+	cli.mu.Lock()
+	defer cli.mu.Unlock()
+	if cli.conn == nil {
+		return nil, ErrNotConnected
+	}
+	if cli.win == nil || cli.win.conn != cli.conn {
+		inbound := cli.inboundSize
+		if cli.handler != nil {
+			inbound = 0
+		}
+		cli.win = newWindow(cli.conn, cli.windowSize, cli.responseTimeout, inbound)
+		go cli.readLoop(cli.win)
+		go cli.keepalive(cli.win, cli.enquireInterval, cli.enquireMissed)
+	}
+	return cli.win, nil
+}
+
+// 发送请求并返回等待响应的 Future, cb 不为 nil 时在完成时于单独的协程中回调
+// 窗口已满时阻塞直到有请求完成或 ctx 结束; 调用 Close 后返回 ErrClientClosed
+func (cli *Client) SendAsync(ctx context.Context, p pkg.Packer, cb func(resp pkg.Packer, err error)) (*Future, error) {
+	w, err := cli.window()
+	if err != nil {
+		return nil, err
+	}
+	return cli.sendAsync(ctx, w, p, cb)
+}
+
+func (cli *Client) sendAsync(ctx context.Context, w *window, p pkg.Packer, cb func(resp pkg.Packer, err error)) (*Future, error) {
+	cli.mu.Lock()
+	closing := cli.closing
+	cli.mu.Unlock()
+	if closing {
+		return nil, ErrClientClosed
+	}
+	if err := cli.wait(ctx, p); err != nil {
+		return nil, err
+	}
+	return cli.send(ctx, w, p, cb)
+}
+
+func (cli *Client) send(ctx context.Context, w *window, p pkg.Packer, cb func(resp pkg.Packer, err error)) (*Future, error) {
+	select {
+	case w.slots <- struct{}{}:
+	case <-w.closed:
+		return nil, w.error()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	seq := <-w.conn.SequenceNum
+	f := &Future{SequenceNum: seq, Request: p, done: make(chan struct{}), cb: cb}
+	if err := w.add(f); err != nil {
+		<-w.slots
+		return nil, err
+	}
+	if err := w.conn.SendPkt(p, seq); err != nil {
+		if w.take(seq) == f {
+			f.complete(nil, err)
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// 当前等待响应的请求数
+func (cli *Client) InFlight() int {
+	cli.mu.Lock()
+	w := cli.win
+	cli.mu.Unlock()
+	if w == nil {
+		return 0
+	}
+	return len(w.slots)
+}
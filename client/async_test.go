@@ -0,0 +1,315 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/boxtsecond/gosmpp/pkg"
+)
+
+func TestSendAsyncMatchesResponses(t *testing.T) {
+	const n = 8
+
+	// 收齐 n 个请求后倒序响应
+	var (
+		mu   sync.Mutex
+		reqs []*pkg.SmppSubmitReqPkt
+	)
+	s := newTestSMSC(func(c *pkg.Conn, p pkg.Packer) {
+		r, ok := p.(*pkg.SmppSubmitReqPkt)
+		if !ok {
+			acceptAll(c, p)
+			return
+		}
+		mu.Lock()
+		reqs = append(reqs, r)
+		all := len(reqs) == n
+		mu.Unlock()
+		if all {
+			go func() {
+				for i := n - 1; i >= 0; i-- {
+					acceptAll(c, reqs[i])
+				}
+			}()
+		}
+	})
+	cli := newTestClient(t, s, WithWindow(n, time.Second))
+
+	futures := make([]*Future, n)
+	for i := range futures {
+		f, err := cli.SendAsync(context.Background(), submit(fmt.Sprint(i)), nil)
+		if err != nil {
+			t.Fatalf("SendAsync() error = %v", err)
+		}
+		futures[i] = f
+	}
+
+	for i, f := range futures {
+		resp, err := f.Wait(context.Background())
+		if err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+		r, ok := resp.(*pkg.SmppSubmitRespPkt)
+		if !ok || r.MsgID != fmt.Sprint(i) || r.SequenceNum != f.SequenceNum {
+			t.Errorf("future %d got %+v", i, resp)
+		}
+	}
+	if cli.InFlight() != 0 {
+		t.Errorf("InFlight() = %d, want 0", cli.InFlight())
+	}
+}
+
+func TestSendAsyncCallback(t *testing.T) {
+	cli := newTestClient(t, newTestSMSC(nil))
+
+	done := make(chan pkg.Packer, 1)
+	_, err := cli.SendAsync(context.Background(), submit("1"), func(resp pkg.Packer, err error) {
+		done <- resp
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case resp := <-done:
+		if _, ok := resp.(*pkg.SmppSubmitRespPkt); !ok {
+			t.Errorf("callback got %T", resp)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("callback was not called")
+	}
+}
+
+func TestCallbackCanSend(t *testing.T) {
+	cli := newTestClient(t, newTestSMSC(nil), WithWindow(2, 5*time.Second))
+
+	// 回调中同步发送请求, 不能阻塞读取协程
+	done := make(chan error, 1)
+	_, err := cli.SendAsync(context.Background(), submit("1"), func(resp pkg.Packer, err error) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err = cli.Submit(ctx, submit("2"))
+		done <- err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Submit() in callback error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Submit() in callback did not return")
+	}
+}
+
+func TestWindowFull(t *testing.T) {
+	s := newTestSMSC(func(c *pkg.Conn, p pkg.Packer) {}) // 从不响应
+	cli := newTestClient(t, s, WithWindow(2, time.Minute))
+
+	for i := 0; i < 2; i++ {
+		if _, err := cli.SendAsync(context.Background(), submit("1"), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cli.InFlight() != 2 {
+		t.Errorf("InFlight() = %d, want 2", cli.InFlight())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := cli.SendAsync(ctx, submit("1"), nil); err != context.DeadlineExceeded {
+		t.Errorf("SendAsync() on a full window error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestResponseTimeout(t *testing.T) {
+	s := newTestSMSC(func(c *pkg.Conn, p pkg.Packer) {})
+	cli := newTestClient(t, s, WithWindow(1, 30*time.Millisecond))
+
+	f, err := cli.SendAsync(context.Background(), submit("1"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Result(); err != ErrResponseTimeout {
+		t.Errorf("Result() error = %v, want %v", err, ErrResponseTimeout)
+	}
+
+	// 超时后释放窗口
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := cli.SendAsync(ctx, submit("1"), nil); err != nil {
+		t.Errorf("SendAsync() after timeout error = %v", err)
+	}
+}
+
+func TestUnmatchedResponsesAreDropped(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"default inbound queue", nil},
+		{"small inbound queue", []Option{WithInbound(1)}},
+		{"handler", []Option{WithHandler(func(pkg.Packer) pkg.Packer { return nil })}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 先发送大量不匹配任何请求的响应, 再正常响应
+			s := newTestSMSC(func(c *pkg.Conn, p pkg.Packer) {
+				if r, ok := p.(*pkg.SmppSubmitReqPkt); ok {
+					for i := uint32(0); i < 2*DefaultWindow; i++ {
+						c.SendPkt(&pkg.SmppSubmitRespPkt{MsgID: "stale"}, r.SequenceNum+1000+i)
+					}
+				}
+				acceptAll(c, p)
+			})
+			cli := newTestClient(t, s, append(tt.opts, WithWindow(1, time.Second))...)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			for i := 0; i < 3; i++ {
+				resp, err := cli.Submit(ctx, submit("ok"))
+				if err != nil {
+					t.Fatalf("Submit() error = %v", err)
+				}
+				if resp.MsgID != "ok" {
+					t.Errorf("Submit() matched %q, want %q", resp.MsgID, "ok")
+				}
+			}
+		})
+	}
+}
+
+func TestDisconnectFailsPending(t *testing.T) {
+	s := newTestSMSC(func(c *pkg.Conn, p pkg.Packer) {
+		if _, ok := p.(*pkg.SmppSubmitReqPkt); ok {
+			c.Close()
+		}
+	})
+	cli := newTestClient(t, s)
+
+	f, err := cli.SendAsync(context.Background(), submit("1"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Result(); err == nil {
+		t.Error("Result() should fail after the peer closes the connection")
+	}
+	select {
+	case <-cli.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Done() was not closed")
+	}
+	if _, err := cli.SendAsync(context.Background(), submit("1"), nil); err == nil {
+		t.Error("SendAsync() should fail on a closed connection")
+	}
+}
+
+func TestInboundQueue(t *testing.T) {
+	resps := make(chan pkg.Packer, 2)
+	s := newTestSMSC(func(c *pkg.Conn, p pkg.Packer) {
+		if _, ok := p.(*pkg.SmppDeliverRespPkt); ok {
+			resps <- p
+		}
+	})
+	cli := newTestClient(t, s, WithInbound(1))
+
+	c := s.last()
+	c.SendPkt(&pkg.SmppDeliverReqPkt{SourceAddr: "1", ShortMessage: "first", SmLength: 5}, 1)
+	c.SendPkt(&pkg.SmppDeliverReqPkt{SourceAddr: "1", ShortMessage: "second", SmLength: 6}, 2)
+
+	// 队列已满时以 ESME_RMSGQFUL 拒绝
+	select {
+	case p := <-resps:
+		r, ok := p.(*pkg.SmppDeliverRespPkt)
+		if !ok || r.SequenceNum != 2 || r.Status != pkg.ESME_RMSGQFUL {
+			t.Errorf("got %T %+v, want deliver_sm_resp ESME_RMSGQFUL for seq 2", p, p)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the second deliver_sm was not rejected")
+	}
+
+	p, err := cli.RecvAndUnpackPkt(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, ok := p.(*pkg.SmppDeliverReqPkt); !ok || d.ShortMessage != "first" {
+		t.Errorf("RecvAndUnpackPkt() = %+v", p)
+	}
+}
+
+func TestSendReqPktResponses(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"inbound queue", nil},
+		{"handler", []Option{WithHandler(func(pkg.Packer) pkg.Packer { return nil })}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := newTestClient(t, newTestSMSC(nil), tt.opts...)
+
+			// 未读取的响应超过窗口大小时也不阻塞发送
+			want := make(map[uint32]bool)
+			for i := 0; i < 2*DefaultWindow; i++ {
+				seq, err := cli.SendReqPkt(submit("1"))
+				if err != nil {
+					t.Fatalf("SendReqPkt() error = %v", err)
+				}
+				want[seq] = true
+			}
+			seqs, err := cli.SendMessage(&pkg.Message{DestinationAddr: "2", Text: strings.Repeat("a", 400)})
+			if err != nil || len(seqs) != 3 {
+				t.Fatalf("SendMessage() = %v, %v", seqs, err)
+			}
+			for _, seq := range seqs {
+				want[seq] = true
+			}
+
+			for len(want) > 0 {
+				p, err := cli.RecvAndUnpackPkt(time.Second)
+				if err != nil {
+					t.Fatalf("RecvAndUnpackPkt() error = %v, %d responses missing", err, len(want))
+				}
+				r, ok := p.(*pkg.SmppSubmitRespPkt)
+				if !ok || !want[r.SequenceNum] {
+					t.Fatalf("RecvAndUnpackPkt() = %T %+v", p, p)
+				}
+				delete(want, r.SequenceNum)
+			}
+			if _, err := cli.RecvAndUnpackPkt(10 * time.Millisecond); err != pkg.ErrReadHeaderTimeout {
+				t.Errorf("RecvAndUnpackPkt() error = %v, want %v", err, pkg.ErrReadHeaderTimeout)
+			}
+		})
+	}
+}
+
+func TestRecvAfterDisconnect(t *testing.T) {
+	s := newTestSMSC(nil)
+	cli := newTestClient(t, s, WithHandler(func(pkg.Packer) pkg.Packer { return nil }))
+	if _, err := cli.SendReqPkt(submit("1")); err != nil {
+		t.Fatal(err)
+	}
+	if !eventually(t, time.Second, func() bool { return cli.InFlight() == 0 }) {
+		t.Fatal("no response")
+	}
+	s.last().Close()
+	<-cli.Done()
+
+	// 断开前收到的响应仍可读取
+	if p, err := cli.RecvAndUnpackPkt(time.Second); err != nil {
+		t.Fatalf("RecvAndUnpackPkt() error = %v", err)
+	} else if _, ok := p.(*pkg.SmppSubmitRespPkt); !ok {
+		t.Fatalf("RecvAndUnpackPkt() = %T", p)
+	}
+	if _, err := cli.RecvAndUnpackPkt(time.Second); err == nil || err == pkg.ErrReadHeaderTimeout {
+		t.Errorf("RecvAndUnpackPkt() error = %v, want the disconnect reason", err)
+	}
+}
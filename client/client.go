@@ -1,8 +1,13 @@
 package client
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
+	"log"
 	"net"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/boxtsecond/gosmpp/pkg"
@@ -13,21 +18,183 @@ var ErrRespNotMatch = errors.New("the response is not matched with the request")
 type Client struct {
 	conn *pkg.Conn
 	ver  uint8
+
+	profile   *pkg.Profile
+	overrides []func(*pkg.Profile) // 处理完所有 Option 后在 profile 的副本上修改
+
+	// 异步发送
+	windowSize      int
+	responseTimeout time.Duration
+	handler         Handler
+	inboundSize     int
+	errorLog        *log.Logger
+
+	// 链路检测
+	enquireInterval time.Duration
+	enquireMissed   int
+
+	// 限速与重试
+	limiter *Limiter
+	retry   *RetryPolicy
+
+	dialer    Dialer
+	tlsConfig *tls.Config
+
+	mu      sync.Mutex
+	win     *window
+	closing bool // 已调用 Close, 不再发送新的请求
 }
 
-func NewClient(version uint8) *Client {
-	return &Client{
-		ver: version,
+type Option func(*Client)
+
+// 设置该 SMSC 的编码习惯, 用于 submit_sm 的编码与 deliver_sm 的解码
+// 为 nil 时使用 pkg.DefaultProfile, 与 WithSegmenter 等选项的先后顺序无关
+func WithProfile(pf *pkg.Profile) Option {
+	return func(cli *Client) {
+		cli.profile = pf
 	}
 }
 
+// 设置该 SMSC 使用的长短信拆分器, 如拼接方式、参考号位数
+func WithSegmenter(s *pkg.Segmenter) Option {
+	return withOverride(func(pf *pkg.Profile) {
+		pf.Segmenter = s
+	})
+}
+
+// 设置该 SMSC 对 data_coding 0 的解释, 取 pkg.ASCII (GSM 7 bit)、pkg.IA5 或 pkg.LATIN1
+func WithDefaultAlphabet(dataCoding uint8) Option {
+	return withOverride(func(pf *pkg.Profile) {
+		pf.DefaultAlphabet = dataCoding
+	})
+}
+
+// 设置该 SMSC 对某个 data_coding 的编解码, 不影响其他连接
+func WithCodec(dataCoding uint8, c pkg.Codec) Option {
+	return withOverride(func(pf *pkg.Profile) {
+		pf.Codecs[dataCoding] = c
+	})
+}
+
+// 原本需要 UCS2 的短信先尝试替换为 GSM 7 bit 字符, onSubstitute 不为 nil 时在发生替换时回调
+func WithTransliterator(t *pkg.Transliterator, onSubstitute func(text string, subs []pkg.Substitution)) Option {
+	return withOverride(func(pf *pkg.Profile) {
+		pf.Transliterator = t
+		pf.OnTransliterate = onSubstitute
+	})
+}
+
+func withOverride(f func(*pkg.Profile)) Option {
+	return func(cli *Client) {
+		cli.overrides = append(cli.overrides, f)
+	}
+}
+
+// 建立到 SMSC 的连接, 可以返回 Unix socket、代理或 net.Pipe 等任意 net.Conn
+type Dialer func(ctx context.Context, addr string) (net.Conn, error)
+
+// 替换默认的 TCP 连接方式, 设置了 WithTLS 时在返回的连接上进行 TLS 握手
+func WithDialer(d Dialer) Option {
+	return func(cli *Client) {
+		cli.dialer = d
+	}
+}
+
+// 使用 TLS 连接 SMSC, 需要双向认证时在 config 中设置客户端证书
+func WithTLS(config *tls.Config) Option {
+	return func(cli *Client) {
+		cli.tlsConfig = config
+	}
+}
+
+func NewClient(version uint8, opts ...Option) *Client {
+	cli := &Client{
+		ver:      version,
+		profile:  pkg.DefaultProfile,
+		errorLog: log.New(os.Stderr, "smpp client: ", log.LstdFlags),
+
+		inboundSize:     DefaultWindow,
+		enquireInterval: DefaultEnquireLinkInterval,
+		enquireMissed:   DefaultEnquireLinkMissed,
+	}
+	for _, opt := range opts {
+		opt(cli)
+	}
+	cli.applyOverrides()
+	return cli
+}
+
+// 在 profile 的副本上应用 WithSegmenter 等选项, 不修改调用方传入的 Profile
+func (cli *Client) applyOverrides() {
+	if cli.profile == nil {
+		cli.profile = pkg.DefaultProfile
+	}
+	if len(cli.overrides) == 0 {
+		return
+	}
+	pf := *cli.profile
+	pf.Codecs = make(map[uint8]pkg.Codec, len(cli.profile.Codecs)+1)
+	for k, v := range cli.profile.Codecs {
+		pf.Codecs[k] = v
+	}
+	for _, f := range cli.overrides {
+		f(&pf)
+	}
+	cli.profile = &pf
+	cli.overrides = nil
+}
+
+func (cli *Client) dial(addr string, timeout time.Duration) (net.Conn, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+	if cli.dialer != nil {
+		conn, err = cli.dialer(ctx, addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil || cli.tlsConfig == nil {
+		return conn, err
+	}
+
+	config := cli.tlsConfig
+	if config.ServerName == "" {
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			config = config.Clone()
+			config.ServerName = host
+		}
+	}
+	tc := tls.Client(conn, config)
+	if deadline, ok := ctx.Deadline(); ok {
+		tc.SetDeadline(deadline)
+	}
+	if err := tc.Handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	tc.SetDeadline(time.Time{})
+	return tc, nil
+}
+
 func (cli *Client) Connect(serverAddr, systemID, password, systemType string, addrTON, addrNPI uint8, addrRange string, timeout time.Duration) error {
 	var err error
-	conn, err := net.DialTimeout("tcp", serverAddr, timeout)
+	conn, err := cli.dial(serverAddr, timeout)
 	if err != nil {
 		return err
 	}
+	cli.mu.Lock()
 	cli.conn = pkg.NewConnection(conn, cli.ver)
+	cli.conn.Profile = cli.profile
+	cli.closing = false
+	cli.mu.Unlock()
 	defer func() {
 		if err != nil {
 			if cli.conn != nil {
@@ -70,26 +237,145 @@ func (cli *Client) Connect(serverAddr, systemID, password, systemType string, ad
 	}
 
 	cli.conn.SetState(pkg.CONNECTION_AUTHOK)
-	return nil
+
+	// 登录后由客户端读取所有 PDU, 请求类 PDU 交给 Handler 或通过 RecvAndUnpackPkt 读取
+	_, err = cli.window()
+	return err
+}
+
+// 优雅关闭: 不再发送新的请求, 等待已发送的请求收到响应, 然后发送 unbind 并等待 unbind_resp, 最后关闭连接
+// ctx 结束时不再等待, 直接关闭连接并返回 ctx.Err()
+func (cli *Client) Close(ctx context.Context) error {
+	cli.mu.Lock()
+	cli.closing = true
+	cli.mu.Unlock()
+
+	w, err := cli.window()
+	if err != nil {
+		return err
+	}
+	defer cli.Disconnect()
+
+	if err := w.drain(ctx); err != nil {
+		return err
+	}
+	f, err := cli.send(ctx, w, &pkg.SmppUnbindReqPkt{}, nil)
+	if err != nil {
+		return err
+	}
+	_, err = f.Wait(ctx)
+	return err
 }
 
+// 直接关闭连接, 等待响应的请求以连接断开的错误结束
 func (cli *Client) Disconnect() {
 	if cli.conn != nil {
 		cli.conn.Close()
 	}
 }
 
+// 发送请求并返回序列号, 登录后响应通过 RecvAndUnpackPkt 读取; 需要等待响应时使用 Do
 func (cli *Client) SendReqPkt(packet pkg.Packer) (uint32, error) {
-	seq := <-cli.conn.SequenceNum
-	return seq, cli.conn.SendPkt(packet, seq)
+	w := cli.current()
+	if w == nil {
+		// 登录前没有读取协程, 由调用方直接读取响应
+		if err := cli.wait(context.Background(), packet); err != nil {
+			return 0, err
+		}
+		seq := <-cli.conn.SequenceNum
+		return seq, cli.conn.SendPkt(packet, seq)
+	}
+
+	f, err := cli.sendAsync(context.Background(), w, packet, func(resp pkg.Packer, err error) {
+		if err != nil {
+			cli.errorLog.Printf("request to %v failed: %v\n", w.conn.RemoteAddr(), err)
+			return
+		}
+		w.pushReply(resp)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return f.SequenceNum, nil
 }
 
 func (cli *Client) SendRspPkt(packet pkg.Packer, sequenceID uint32) error {
 	return cli.conn.SendPkt(packet, sequenceID)
 }
 
+// 读取协程启动后, 读取 SendReqPkt 发送的请求收到的响应, 以及 WithInbound 的队列中 SMSC 发来的请求
+// 设置了 WithHandler 时只读取响应
 func (cli *Client) RecvAndUnpackPkt(timeout time.Duration) (interface{}, error) {
-	return cli.conn.RecvAndUnpackPkt(timeout)
+	w := cli.current()
+	if w == nil {
+		return cli.conn.RecvAndUnpackPkt(timeout)
+	}
+
+	var expired <-chan time.Time
+	if timeout != 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		expired = t.C
+	}
+	// 队列在连接断开时关闭, 没有队列时等待连接断开
+	inbound := w.inbound
+	var closed <-chan struct{}
+	if inbound == nil {
+		closed = w.closed
+	}
+	for {
+		if p := w.popReply(); p != nil {
+			return p, nil
+		}
+		select {
+		case <-w.replied:
+		case p, ok := <-inbound:
+			if ok {
+				return p, nil
+			}
+			inbound, closed = nil, w.closed
+		case <-closed:
+			if p := w.popReply(); p != nil {
+				return p, nil
+			}
+			return nil, w.error()
+		case <-expired:
+			return nil, pkg.ErrReadHeaderTimeout
+		}
+	}
+}
+
+// 按该 SMSC 的编码习惯编码并拆分 submit_sm
+func (cli *Client) GetMsgPkgs(p *pkg.SmppSubmitReqPkt) ([]*pkg.SmppSubmitReqPkt, error) {
+	return cli.profile.GetMsgPkgs(p)
+}
+
+// 按该 SMSC 的编码习惯将短信编译为需要依次发送的 PDU
+func (cli *Client) GetMessagePkgs(m *pkg.Message) ([]pkg.Packer, error) {
+	return cli.profile.GetMessagePkgs(m)
+}
+
+// 编译并依次发送短信, 返回每个 PDU 的序列号, 登录后响应通过 RecvAndUnpackPkt 读取
+// 需要等待响应时使用 SubmitMessage
+func (cli *Client) SendMessage(m *pkg.Message) ([]uint32, error) {
+	packets, err := cli.GetMessagePkgs(m)
+	if err != nil {
+		return nil, err
+	}
+	seqs := make([]uint32, 0, len(packets))
+	for _, p := range packets {
+		seq, err := cli.SendReqPkt(p)
+		if err != nil {
+			return seqs, err
+		}
+		seqs = append(seqs, seq)
+	}
+	return seqs, nil
+}
+
+// 按该 SMSC 的编码习惯解码 deliver_sm 的内容
+func (cli *Client) DecodeDeliver(p *pkg.SmppDeliverReqPkt) (string, error) {
+	return cli.profile.DecodeDeliver(p)
 }
 
 func (cli *Client) GetConn() *pkg.Conn {
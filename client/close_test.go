@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/boxtsecond/gosmpp/pkg"
+)
+
+func TestCloseDrainsThenUnbinds(t *testing.T) {
+	// submit_sm 延迟响应, 记录 SMSC 发出响应与收到 unbind 的顺序
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	record := func(e string) {
+		mu.Lock()
+		events = append(events, e)
+		mu.Unlock()
+	}
+	s := newTestSMSC(func(c *pkg.Conn, p pkg.Packer) {
+		switch p.(type) {
+		case *pkg.SmppSubmitReqPkt:
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				record("submit_sm_resp")
+				acceptAll(c, p)
+			}()
+			return
+		case *pkg.SmppUnbindReqPkt:
+			record("unbind")
+		}
+		acceptAll(c, p)
+	})
+	cli := newTestClient(t, s)
+
+	f, err := cli.SendAsync(context.Background(), submit("1"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := cli.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := f.Result(); err != nil {
+		t.Errorf("pending request error = %v, want its response", err)
+	}
+
+	mu.Lock()
+	got := append([]string(nil), events...)
+	mu.Unlock()
+	if len(got) != 2 || got[0] != "submit_sm_resp" || got[1] != "unbind" {
+		t.Errorf("events = %v, want [submit_sm_resp unbind]", got)
+	}
+
+	if _, err := cli.SendAsync(context.Background(), submit("1"), nil); err != ErrClientClosed {
+		t.Errorf("SendAsync() after Close error = %v, want %v", err, ErrClientClosed)
+	}
+}
+
+func TestCloseRejectsWaitingRequests(t *testing.T) {
+	// unbind_resp 延迟返回, 记录 unbind 之后收到的 submit_sm
+	var (
+		mu      sync.Mutex
+		unbound bool
+		late    int
+	)
+	s := newTestSMSC(func(c *pkg.Conn, p pkg.Packer) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch p.(type) {
+		case *pkg.SmppSubmitReqPkt:
+			if unbound {
+				late++
+			}
+		case *pkg.SmppUnbindReqPkt:
+			unbound = true
+			go func() {
+				time.Sleep(200 * time.Millisecond)
+				acceptAll(c, p)
+			}()
+			return
+		}
+		acceptAll(c, p)
+	})
+	cli := newTestClient(t, s, WithRateLimit(10, 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := cli.Submit(ctx, submit("1")); err != nil {
+		t.Fatal(err)
+	}
+	// 第二个请求在限速中等待时开始 Close
+	errc := make(chan error, 1)
+	go func() {
+		_, err := cli.SendAsync(ctx, submit("2"), nil)
+		errc <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if err := cli.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if err := <-errc; err != ErrClientClosed {
+		t.Errorf("SendAsync() during Close error = %v, want %v", err, ErrClientClosed)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if late != 0 {
+		t.Errorf("SMSC received %d submit_sm after unbind", late)
+	}
+}
+
+func TestCloseDeadline(t *testing.T) {
+	s := newTestSMSC(func(c *pkg.Conn, p pkg.Packer) {
+		if _, ok := p.(*pkg.SmppSubmitReqPkt); ok {
+			return // 从不响应
+		}
+		acceptAll(c, p)
+	})
+	cli := newTestClient(t, s)
+
+	f, err := cli.SendAsync(context.Background(), submit("1"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if err := cli.Close(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Close() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	// 超时后直接断开连接
+	if _, err := f.Result(); err == nil {
+		t.Error("pending request should fail after Close gives up")
+	}
+}
+
+func TestPeerUnbind(t *testing.T) {
+	got := make(chan pkg.Packer, 1)
+	s := recordingSMSC(got)
+	cli := newTestClient(t, s)
+
+	s.last().SendPkt(&pkg.SmppUnbindReqPkt{}, 5)
+	select {
+	case p := <-got:
+		if r, ok := p.(*pkg.SmppUnbindRespPkt); !ok || r.SequenceNum != 5 {
+			t.Errorf("got %T %+v, want unbind_resp for seq 5", p, p)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("unbind was not answered")
+	}
+
+	select {
+	case <-cli.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Done() was not closed")
+	}
+	if err := cli.Err(); err != ErrUnbound {
+		t.Errorf("Err() = %v, want %v", err, ErrUnbound)
+	}
+	if _, err := cli.SendAsync(context.Background(), submit("1"), nil); err != ErrUnbound {
+		t.Errorf("SendAsync() after unbind error = %v, want %v", err, ErrUnbound)
+	}
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/boxtsecond/gosmpp/pkg"
+)
+
+var ErrLinkDead = errors.New("smpp client: no enquire_link response returned")
+
+const (
+	DefaultEnquireLinkInterval = 30 * time.Second // 默认 enquire_link_timer
+	DefaultEnquireLinkMissed   = 3                // 默认连续未收到响应多少次后认为连接已断开
+)
+
+// 连接空闲 interval 后发送 enquire_link, 连续 n 次未收到对端的任何 PDU 时断开连接
+// 断开原因为 ErrLinkDead, 通过 Done 与 Err 获取, 等待中的请求同样以 ErrLinkDead 结束
+// interval 不大于 0 时不发送, n 不大于 0 时使用 DefaultEnquireLinkMissed
+func WithEnquireLink(interval time.Duration, n int) Option {
+	return func(cli *Client) {
+		cli.enquireInterval = interval
+		cli.enquireMissed = n
+	}
+}
+
+// 定时检查连接是否空闲, 由 Connect 启动, 连接断开后退出
+func (cli *Client) keepalive(w *window, interval time.Duration, n int) {
+	if interval <= 0 {
+		return
+	}
+	if n <= 0 {
+		n = DefaultEnquireLinkMissed
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-w.closed:
+			return
+		case <-t.C:
+			if time.Since(time.Unix(0, atomic.LoadInt64(&w.lastRead))) < interval {
+				continue
+			}
+			if atomic.LoadInt32(&w.missed) >= int32(n) {
+				w.close(ErrLinkDead)
+				w.conn.Conn.Close() // 结束读取协程, 连接由 Disconnect 释放
+				return
+			}
+			if err := w.conn.SendPkt(&pkg.SmppEnquireLinkReqPkt{}, <-w.conn.SequenceNum); err == nil {
+				atomic.AddInt32(&w.missed, 1)
+			}
+		}
+	}
+}
+
+// 当前连接断开时关闭, 未连接时返回已关闭的 channel
+func (cli *Client) Done() <-chan struct{} {
+	cli.mu.Lock()
+	defer cli.mu.Unlock()
+	if cli.win == nil || cli.win.conn != cli.conn {
+		closed := make(chan struct{})
+		close(closed)
+		return closed
+	}
+	return cli.win.closed
+}
+
+// 当前连接断开的原因, 如 ErrLinkDead; 连接正常时返回 nil
+func (cli *Client) Err() error {
+	cli.mu.Lock()
+	defer cli.mu.Unlock()
+	if cli.win == nil || cli.win.conn != cli.conn {
+		return ErrNotConnected
+	}
+	return cli.win.error()
+}
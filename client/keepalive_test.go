@@ -0,0 +1,155 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/boxtsecond/gosmpp/pkg"
+)
+
+// 记录 SMSC 收到的所有 PDU, 请求以 ESME_ROK 响应
+func recordingSMSC(ch chan<- pkg.Packer) *testSMSC {
+	return newTestSMSC(func(c *pkg.Conn, p pkg.Packer) {
+		ch <- p
+		acceptAll(c, p)
+	})
+}
+
+func TestHandlerResponse(t *testing.T) {
+	handler := func(req pkg.Packer) pkg.Packer {
+		switch req.(type) {
+		case *pkg.SmppDeliverReqPkt:
+			return &pkg.SmppDeliverRespPkt{Status: pkg.ESME_RX_T_APPN}
+		case *pkg.SmppDataReqPkt:
+			return &pkg.SmppDataRespPkt{}
+		}
+		return nil
+	}
+	tests := []struct {
+		name   string
+		req    pkg.Packer
+		cmd    pkg.CommandID
+		status pkg.Status
+	}{
+		{"deliver_sm", &pkg.SmppDeliverReqPkt{SourceAddr: "1"}, pkg.SMPP_DELIVER_RESP, pkg.ESME_RX_T_APPN},
+		{"data_sm", &pkg.SmppDataReqPkt{SourceAddr: "1"}, pkg.SMPP_DATA_RESP, pkg.ESME_ROK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(chan pkg.Packer, 1)
+			s := recordingSMSC(got)
+			newTestClient(t, s, WithHandler(handler))
+
+			s.last().SendPkt(tt.req, 7)
+			select {
+			case p := <-got:
+				cmd, status := responseStatus(p)
+				seq, _ := responseSeq(p)
+				if cmd != tt.cmd || status != tt.status || seq != 7 {
+					t.Errorf("got %s status %s seq %d, want %s status %s seq 7", cmd, status, seq, tt.cmd, tt.status)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("the request was not answered")
+			}
+		})
+	}
+}
+
+func TestRequestsQueuedByDefault(t *testing.T) {
+	got := make(chan pkg.Packer, 1)
+	s := recordingSMSC(got)
+	cli := newTestClient(t, s)
+
+	// 未设置 Handler 时不自动响应, 由调用方读取并响应
+	s.last().SendPkt(&pkg.SmppDeliverReqPkt{SourceAddr: "1", ShortMessage: "hi", SmLength: 2}, 7)
+	p, err := cli.RecvAndUnpackPkt(time.Second)
+	if err != nil {
+		t.Fatalf("RecvAndUnpackPkt() error = %v", err)
+	}
+	d, ok := p.(*pkg.SmppDeliverReqPkt)
+	if !ok || d.ShortMessage != "hi" {
+		t.Fatalf("RecvAndUnpackPkt() = %+v", p)
+	}
+	select {
+	case p := <-got:
+		t.Fatalf("deliver_sm was answered automatically: %T", p)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if err := cli.SendRspPkt(&pkg.SmppDeliverRespPkt{}, d.SequenceNum); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case p := <-got:
+		if r, ok := p.(*pkg.SmppDeliverRespPkt); !ok || r.SequenceNum != 7 {
+			t.Errorf("got %T %+v, want deliver_sm_resp for seq 7", p, p)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("deliver_sm_resp was not sent")
+	}
+}
+
+func TestAnswerEnquireLink(t *testing.T) {
+	got := make(chan pkg.Packer, 1)
+	s := recordingSMSC(got)
+	newTestClient(t, s)
+
+	s.last().SendPkt(&pkg.SmppEnquireLinkReqPkt{}, 9)
+	select {
+	case p := <-got:
+		if r, ok := p.(*pkg.SmppEnquireLinkRespPkt); !ok || r.SequenceNum != 9 {
+			t.Errorf("got %T %+v, want enquire_link_resp for seq 9", p, p)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("enquire_link was not answered")
+	}
+}
+
+func TestEnquireLink(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer bool
+		err    error
+	}{
+		{"answered", true, nil},
+		{"ignored", false, ErrLinkDead},
+	}
+
+	for _, tt := range tests {
+		tt := tt // SMSC 的读取协程可能晚于子测试结束
+		t.Run(tt.name, func(t *testing.T) {
+			enquired := make(chan struct{}, 16)
+			s := newTestSMSC(func(c *pkg.Conn, p pkg.Packer) {
+				if _, ok := p.(*pkg.SmppEnquireLinkReqPkt); ok {
+					select {
+					case enquired <- struct{}{}:
+					default:
+					}
+					if !tt.answer {
+						return
+					}
+				}
+				acceptAll(c, p)
+			})
+			cli := newTestClient(t, s, WithEnquireLink(20*time.Millisecond, 2))
+
+			select {
+			case <-cli.Done():
+				if tt.err == nil {
+					t.Fatalf("connection closed: %v", cli.Err())
+				}
+			case <-time.After(300 * time.Millisecond):
+				if tt.err != nil {
+					t.Fatal("Done() was not closed")
+				}
+			}
+			if err := cli.Err(); err != tt.err {
+				t.Errorf("Err() = %v, want %v", err, tt.err)
+			}
+			if len(enquired) < 2 {
+				t.Errorf("sent %d enquire_link, want at least 2", len(enquired))
+			}
+		})
+	}
+}
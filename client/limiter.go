@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+var ErrRateLimited = errors.New("smpp client: rate limit exceeds context deadline")
+
+const (
+	throttleFactor = 0.5         // 收到 ESME_RTHROTTLED 后速率乘以该值
+	throttleHold   = time.Second // 该时间内多次收到 ESME_RTHROTTLED 只降低一次
+	rampPerSecond  = 0.1         // 之后每秒恢复配置速率的 10%
+)
+
+// 令牌桶限速, 收到 ESME_RTHROTTLED 后降低速率, 之后逐渐恢复到配置的速率
+// 可以由多个 Client 共用, 即多个连接共同限速
+type Limiter struct {
+	mu          sync.Mutex
+	limit       float64 // 配置的速率, 条/秒
+	rate        float64 // 当前的速率
+	burst       float64
+	tokens      float64
+	last        time.Time
+	throttledAt time.Time
+}
+
+// 每秒 rate 条, 最多连续发送 burst 条; rate 不大于 0 时不限速, burst 不大于 0 时为 1
+func NewLimiter(rate float64, burst int) *Limiter {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &Limiter{
+		limit:  rate,
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (l *Limiter) advance(now time.Time) {
+	elapsed := now.Sub(l.last).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	l.last = now
+	if l.rate < l.limit {
+		l.rate = math.Min(l.limit, l.rate+l.limit*rampPerSecond*elapsed)
+	}
+	l.tokens = math.Min(l.burst, l.tokens+l.rate*elapsed)
+}
+
+// 等待一个令牌; 需要等待到 ctx 的截止时间之后时立即返回 ErrRateLimited, ctx 结束时返回 ctx.Err()
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l.limit <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.advance(now)
+	l.tokens--
+	var d time.Duration
+	if l.tokens < 0 {
+		d = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	if deadline, ok := ctx.Deadline(); ok && now.Add(d).After(deadline) {
+		l.tokens++
+		l.mu.Unlock()
+		return ErrRateLimited
+	}
+	l.mu.Unlock()
+
+	if d == 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// SMSC 返回 ESME_RTHROTTLED, 降低速率并清空令牌
+func (l *Limiter) Throttled() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if now.Sub(l.throttledAt) < throttleHold {
+		return
+	}
+	l.advance(now)
+	l.throttledAt = now
+	l.rate = math.Max(l.rate*throttleFactor, math.Min(1, l.limit))
+	if l.tokens > 0 {
+		l.tokens = 0
+	}
+}
+
+// 当前的速率, 条/秒
+func (l *Limiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.advance(time.Now())
+	return l.rate
+}
+
+// submit_sm 与 data_sm 按每秒 rate 条限速, 每次连接使用新的 Limiter
+func WithRateLimit(rate float64, burst int) Option {
+	return func(cli *Client) {
+		cli.limiter = NewLimiter(rate, burst)
+	}
+}
+
+// submit_sm 与 data_sm 使用 l 限速, 可以在多个连接间共用
+func WithLimiter(l *Limiter) Option {
+	return func(cli *Client) {
+		cli.limiter = l
+	}
+}
+
+// 连接池的所有连接共同限速, 可与每个连接的 WithLimiter 同时使用
+func WithPoolLimiter(l *Limiter) PoolOption {
+	return func(p *Pool) {
+		p.limiter = l
+	}
+}
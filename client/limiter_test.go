@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/boxtsecond/gosmpp/pkg"
+)
+
+func TestLimiterWait(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    float64
+		burst   int
+		n       int           // 先取走的令牌数
+		timeout time.Duration // 第 n+1 次等待的超时时间
+		wantErr error
+		minWait time.Duration
+	}{
+		{"unlimited", 0, 0, 100, time.Millisecond, nil, 0},
+		{"within burst", 10, 3, 2, time.Millisecond, nil, 0},
+		{"exceeds deadline", 10, 1, 1, 10 * time.Millisecond, ErrRateLimited, 0},
+		{"waits for a token", 20, 1, 1, time.Second, nil, 30 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLimiter(tt.rate, tt.burst)
+			for i := 0; i < tt.n; i++ {
+				if err := l.Wait(context.Background()); err != nil {
+					t.Fatalf("Wait() error = %v", err)
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			start := time.Now()
+			if err := l.Wait(ctx); err != tt.wantErr {
+				t.Fatalf("Wait() error = %v, want %v", err, tt.wantErr)
+			}
+			if d := time.Since(start); d < tt.minWait {
+				t.Errorf("Wait() returned after %v, want at least %v", d, tt.minWait)
+			}
+		})
+	}
+}
+
+func TestLimiterCanceled(t *testing.T) {
+	l := NewLimiter(1, 1)
+	l.Wait(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	if err := l.Wait(ctx); err != context.Canceled {
+		t.Fatalf("Wait() error = %v, want %v", err, context.Canceled)
+	}
+	// 取消的等待归还令牌
+	l.mu.Lock()
+	tokens := l.tokens
+	l.mu.Unlock()
+	if tokens < -0.1 {
+		t.Errorf("tokens = %v after a canceled Wait, want about 0", tokens)
+	}
+}
+
+func TestLimiterThrottled(t *testing.T) {
+	tests := []struct {
+		name  string
+		rate  float64
+		times int
+		want  float64
+	}{
+		{"halves the rate", 100, 1, 50},
+		{"once per hold time", 100, 3, 50},
+		{"not below 1", 1.5, 1, 1},
+		{"not above the limit", 0.5, 1, 0.5},
+	}
+	for _, tt := range tests {
+		l := NewLimiter(tt.rate, 1)
+		for i := 0; i < tt.times; i++ {
+			l.Throttled()
+		}
+		// 调用期间的恢复可以忽略
+		if got := l.Rate(); math.Abs(got-tt.want) > 0.01*tt.rate {
+			t.Errorf("%s: Rate() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLimiterRecovers(t *testing.T) {
+	l := NewLimiter(100, 1)
+	l.Throttled()
+
+	// 模拟 ESME_RTHROTTLED 之后过去了 2 秒, 恢复 20%
+	l.mu.Lock()
+	l.last = l.last.Add(-2 * time.Second)
+	l.mu.Unlock()
+	if got := l.Rate(); math.Abs(got-70) > 1 {
+		t.Errorf("Rate() = %v, want about 70", got)
+	}
+
+	l.mu.Lock()
+	l.last = l.last.Add(-time.Minute)
+	l.mu.Unlock()
+	if got := l.Rate(); got != 100 {
+		t.Errorf("Rate() = %v, want 100", got)
+	}
+}
+
+func TestClientThrottled(t *testing.T) {
+	s := newTestSMSC(func(c *pkg.Conn, p pkg.Packer) { respond(c, p, pkg.ESME_RTHROTTLED) })
+	l := NewLimiter(100, 10)
+	cli := newTestClient(t, s, WithLimiter(l))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := cli.Submit(ctx, submit("1")); err == nil {
+		t.Fatal("Submit() should return ESME_RTHROTTLED")
+	}
+	if got := l.Rate(); got > 51 {
+		t.Errorf("Rate() = %v after ESME_RTHROTTLED, want about 50", got)
+	}
+}
@@ -0,0 +1,319 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/boxtsecond/gosmpp/pkg"
+)
+
+// 连接池选择连接的方式
+type PoolStrategy uint8
+
+const (
+	POOL_ROUND_ROBIN  PoolStrategy = iota // 依次使用每个可用的连接
+	POOL_LEAST_LOADED                     // 使用等待响应的请求最少的连接
+)
+
+type PoolOption func(*Pool)
+
+func WithStrategy(strategy PoolStrategy) PoolOption {
+	return func(p *Pool) {
+		p.strategy = strategy
+	}
+}
+
+// 每个连接的会话使用的选项
+func WithSessionOptions(opts ...SessionOption) PoolOption {
+	return func(p *Pool) {
+		p.opts = append(p.opts, opts...)
+	}
+}
+
+// 同一账号的多个连接, 可以连接到一个或多个 SMSC 地址
+// 每个连接各自断线重连, 断开期间不参与发送, 重新登录后恢复
+type Pool struct {
+	strategy PoolStrategy
+	opts     []SessionOption
+	sessions []*Session
+	next     uint32
+	limiter  *Limiter
+	retry    *RetryPolicy
+
+	mu      sync.Mutex
+	changed chan struct{} // 任一连接状态变化时关闭
+}
+
+// binds 中每一项对应一个连接, 同一 SMSC 允许多个连接时重复即可
+func NewPool(version uint8, binds []BindParams, opts ...PoolOption) *Pool {
+	p := &Pool{changed: make(chan struct{})}
+	for _, opt := range opts {
+		opt(p)
+	}
+	for _, b := range binds {
+		s := NewSession(version, b, p.opts...)
+		onState := s.onState
+		s.onState = func(e StateEvent) {
+			if onState != nil {
+				onState(e)
+			}
+			p.notify()
+		}
+		p.sessions = append(p.sessions, s)
+	}
+	return p
+}
+
+func (p *Pool) notify() {
+	p.mu.Lock()
+	close(p.changed)
+	p.changed = make(chan struct{})
+	p.mu.Unlock()
+}
+
+// 启动所有连接
+func (p *Pool) Open() {
+	for _, s := range p.sessions {
+		s.Open()
+	}
+}
+
+// 停止重连并同时优雅关闭所有连接, 返回第一个关闭连接时的错误
+func (p *Pool) Close(ctx context.Context) error {
+	errs := make(chan error, len(p.sessions))
+	for _, s := range p.sessions {
+		go func(s *Session) {
+			errs <- s.Close(ctx)
+		}(s)
+	}
+	var err error
+	for range p.sessions {
+		if e := <-errs; e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (p *Pool) Sessions() []*Session {
+	return p.sessions
+}
+
+// 当前可用的连接数
+func (p *Pool) Healthy() int {
+	n := 0
+	for _, s := range p.sessions {
+		if s.healthy() != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// 按 PoolStrategy 选择一个可用的连接, 优先不使用 exclude, 没有时返回 nil
+func (p *Pool) pick(exclude *Session) (*Session, *Client) {
+	if len(p.sessions) == 0 {
+		return nil, nil
+	}
+	start := int(atomic.AddUint32(&p.next, 1) % uint32(len(p.sessions)))
+
+	var (
+		best    *Session
+		bestCli *Client
+		load    int
+	)
+	for i := range p.sessions {
+		s := p.sessions[(start+i)%len(p.sessions)]
+		if s == exclude {
+			continue
+		}
+		cli := s.healthy()
+		if cli == nil {
+			continue
+		}
+		if p.strategy == POOL_ROUND_ROBIN {
+			return s, cli
+		}
+		if n := cli.InFlight(); bestCli == nil || n < load {
+			best, bestCli, load = s, cli, n
+		}
+	}
+	if bestCli == nil && exclude != nil {
+		return p.pick(nil)
+	}
+	return best, bestCli
+}
+
+// 等待并选择一个可用的连接
+func (p *Pool) Client(ctx context.Context) (*Client, error) {
+	_, cli, err := p.client(ctx, nil)
+	return cli, err
+}
+
+func (p *Pool) client(ctx context.Context, exclude *Session) (*Session, *Client, error) {
+	for {
+		p.mu.Lock()
+		changed := p.changed
+		p.mu.Unlock()
+
+		if s, cli := p.pick(exclude); cli != nil {
+			return s, cli, nil
+		}
+		if p.closed() {
+			return nil, nil, ErrSessionClosed
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+}
+
+func (p *Pool) closed() bool {
+	for _, s := range p.sessions {
+		select {
+		case <-s.closing:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// 选择一个连接发送请求并等待响应, 连接断开时按该连接的 InFlightPolicy 处理, 重新发送时可能使用其他连接
+// 设置了 WithPoolRetry 时按策略重试
+func (p *Pool) Do(ctx context.Context, req pkg.Packer) (pkg.Packer, error) {
+	return p.do(ctx, req, nil)
+}
+
+// via 不为 nil 时只经由该连接发送, 重试与重新发送都不更换连接
+func (p *Pool) do(ctx context.Context, req pkg.Packer, via *Session) (pkg.Packer, error) {
+	if p.retry == nil {
+		_, resp, _, err := p.send(ctx, req, nil, via)
+		return resp, err
+	}
+
+	var last *Session
+	return p.retry.run(ctx, req, func() (pkg.Packer, bool, error) {
+		var exclude *Session
+		if p.retry.OtherBind {
+			exclude = last
+		}
+		s, resp, sent, err := p.send(ctx, req, exclude, via)
+		last = s
+		return resp, sent, err
+	})
+}
+
+// 发送一次请求, 返回使用的连接
+func (p *Pool) send(ctx context.Context, req pkg.Packer, exclude, via *Session) (*Session, pkg.Packer, bool, error) {
+	if p.limiter != nil && isMessage(req) {
+		if err := p.limiter.Wait(ctx); err != nil {
+			return nil, nil, false, err
+		}
+	}
+	for {
+		var (
+			s   = via
+			cli *Client
+			err error
+		)
+		if via != nil {
+			cli, err = via.Client(ctx)
+		} else {
+			s, cli, err = p.client(ctx, exclude)
+		}
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		var (
+			resp pkg.Packer
+			sent = true
+		)
+		if p.retry != nil {
+			resp, sent, err = cli.do(ctx, req)
+		} else {
+			resp, err = cli.Do(ctx, req)
+		}
+		if _, status := responseStatus(resp); status == pkg.ESME_RTHROTTLED && p.limiter != nil {
+			p.limiter.Throttled()
+		}
+		if err == nil || s.policy != INFLIGHT_RESUBMIT || ctx.Err() != nil || cli.Err() == nil {
+			return s, resp, sent, err
+		}
+	}
+}
+
+func (p *Pool) Submit(ctx context.Context, req *pkg.SmppSubmitReqPkt) (*pkg.SmppSubmitRespPkt, error) {
+	resp, err := p.Do(ctx, req)
+	if resp == nil {
+		return nil, err
+	}
+	r, ok := resp.(*pkg.SmppSubmitRespPkt)
+	if !ok {
+		return nil, ErrRespNotMatch
+	}
+	return r, err
+}
+
+func (p *Pool) DataSM(ctx context.Context, req *pkg.SmppDataReqPkt) (*pkg.SmppDataRespPkt, error) {
+	resp, err := p.Do(ctx, req)
+	if resp == nil {
+		return nil, err
+	}
+	r, ok := resp.(*pkg.SmppDataRespPkt)
+	if !ok {
+		return nil, ErrRespNotMatch
+	}
+	return r, err
+}
+
+func (p *Pool) QuerySM(ctx context.Context, req *pkg.SmppQueryReqPkt) (*pkg.SmppQueryRespPkt, error) {
+	resp, err := p.Do(ctx, req)
+	if resp == nil {
+		return nil, err
+	}
+	r, ok := resp.(*pkg.SmppQueryRespPkt)
+	if !ok {
+		return nil, ErrRespNotMatch
+	}
+	return r, err
+}
+
+func (p *Pool) CancelSM(ctx context.Context, req *pkg.SmppCancelReqPkt) (*pkg.SmppCancelRespPkt, error) {
+	resp, err := p.Do(ctx, req)
+	if resp == nil {
+		return nil, err
+	}
+	r, ok := resp.(*pkg.SmppCancelRespPkt)
+	if !ok {
+		return nil, ErrRespNotMatch
+	}
+	return r, err
+}
+
+// 编译短信后依次发送, 长短信的各条都经由同一个连接发送, 以便 SMSC 按连接重组
+// 该连接断开时等待其重连, 不会改用其他连接
+func (p *Pool) SubmitMessage(ctx context.Context, m *pkg.Message) ([]pkg.Packer, error) {
+	s, cli, err := p.client(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	packets, err := cli.GetMessagePkgs(m)
+	if err != nil {
+		return nil, err
+	}
+	resps := make([]pkg.Packer, 0, len(packets))
+	for _, req := range packets {
+		resp, err := p.do(ctx, req, s)
+		if err != nil {
+			return resps, err
+		}
+		resps = append(resps, resp)
+	}
+	return resps, nil
+}
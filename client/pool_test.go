@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/boxtsecond/gosmpp/pkg"
+)
+
+// 按连接统计收到的 submit_sm, 目的号码为 "hold" 时不响应
+type countingSMSC struct {
+	*testSMSC
+	mu     sync.Mutex
+	counts map[*pkg.Conn]int
+}
+
+func newCountingSMSC() *countingSMSC {
+	s := &countingSMSC{counts: make(map[*pkg.Conn]int)}
+	s.testSMSC = newTestSMSC(func(c *pkg.Conn, p pkg.Packer) {
+		if r, ok := p.(*pkg.SmppSubmitReqPkt); ok {
+			s.mu.Lock()
+			s.counts[c]++
+			s.mu.Unlock()
+			if r.DestinationAddr == "hold" {
+				return
+			}
+		}
+		acceptAll(c, p)
+	})
+	return s
+}
+
+// 各连接收到的 submit_sm 数量, 按登录顺序
+func (s *countingSMSC) submits() []int {
+	s.testSMSC.mu.Lock()
+	conns := append([]*pkg.Conn(nil), s.conns...)
+	s.testSMSC.mu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := make([]int, len(conns))
+	for i, c := range conns {
+		n[i] = s.counts[c]
+	}
+	return n
+}
+
+func newTestPool(t *testing.T, s *testSMSC, binds int, opts ...PoolOption) *Pool {
+	t.Helper()
+	opts = append([]PoolOption{WithSessionOptions(
+		WithClientOptions(WithDialer(s.dial), WithErrorLog(discardLog)),
+		WithBackoff(Backoff{Min: MinBackoffDelay}),
+	)}, opts...)
+	params := make([]BindParams, binds)
+	for i := range params {
+		params[i] = testBind()
+	}
+	p := NewPool(pkg.VERSION, params, opts...)
+	p.Open()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		p.Close(ctx)
+	})
+	if !eventually(t, time.Second, func() bool { return p.Healthy() == binds }) {
+		t.Fatalf("Healthy() = %d, want %d", p.Healthy(), binds)
+	}
+	return p
+}
+
+func TestPoolRoundRobin(t *testing.T) {
+	s := newCountingSMSC()
+	p := newTestPool(t, s.testSMSC, 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for i := 0; i < 10; i++ {
+		if _, err := p.Submit(ctx, submit("1")); err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+	}
+	if n := s.submits(); len(n) != 2 || n[0] != 5 || n[1] != 5 {
+		t.Errorf("submits per bind = %v, want [5 5]", n)
+	}
+}
+
+func TestPoolLeastLoaded(t *testing.T) {
+	s := newCountingSMSC()
+	p := newTestPool(t, s.testSMSC, 2, WithStrategy(POOL_LEAST_LOADED))
+
+	// 第一个连接上有 3 个请求等待响应
+	busy := p.Sessions()[0].healthy()
+	for i := 0; i < 3; i++ {
+		if _, err := busy.SendAsync(context.Background(), submit("hold"), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for i := 0; i < 4; i++ {
+		if _, err := p.Submit(ctx, submit("1")); err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+	}
+	if busy.InFlight() != 3 {
+		t.Errorf("busy bind InFlight() = %d, want 3", busy.InFlight())
+	}
+	// 之后的请求都经由另一个连接
+	if n := s.submits(); len(n) != 2 || !(n[0] == 3 && n[1] == 4 || n[0] == 4 && n[1] == 3) {
+		t.Errorf("submits per bind = %v, want 3 and 4", n)
+	}
+}
+
+func TestPoolSubmitMessageUsesOneBind(t *testing.T) {
+	s := newCountingSMSC()
+	p := newTestPool(t, s.testSMSC, 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for i := 0; i < 2; i++ {
+		resps, err := p.SubmitMessage(ctx, &pkg.Message{DestinationAddr: "1", Text: strings.Repeat("a", 400)})
+		if err != nil || len(resps) != 3 {
+			t.Fatalf("SubmitMessage() = %d responses, %v, want 3", len(resps), err)
+		}
+	}
+	// 每条长短信的 3 段都经由同一个连接
+	if n := s.submits(); len(n) != 2 || n[0]%3 != 0 || n[1]%3 != 0 {
+		t.Errorf("submits per bind = %v, want multiples of 3", n)
+	}
+}
+
+func TestPoolClosed(t *testing.T) {
+	p := newTestPool(t, newTestSMSC(nil), 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := p.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if p.Healthy() != 0 {
+		t.Errorf("Healthy() = %d after Close, want 0", p.Healthy())
+	}
+	if _, err := p.Submit(ctx, submit("1")); err != ErrSessionClosed {
+		t.Errorf("Submit() after Close error = %v, want %v", err, ErrSessionClosed)
+	}
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/boxtsecond/gosmpp/pkg"
+)
+
+// 默认重试的 command_status, SMSC 返回这些状态时未接收该短信
+var DefaultRetryStatuses = []pkg.Status{pkg.ESME_RMSGQFUL, pkg.ESME_RTHROTTLED, pkg.ESME_RSYSERR}
+
+// 请求失败时的重试策略
+// 只在确定 SMSC 未接收请求时重试: 返回了需要重试的 command_status, 或请求未完整发送
+// submit_sm 与 data_sm 响应超时或发送后连接断开时, SMSC 可能已接收, 不重试
+type RetryPolicy struct {
+	Statuses    []pkg.Status // 需要重试的 command_status, 为 nil 时使用 DefaultRetryStatuses
+	MaxAttempts int          // 包括第一次在内最多发送的次数
+	Backoff     Backoff      // 每次重试前等待的时间, 为零值时使用 DefaultBackoff
+	Timeout     bool         // query_sm 等不会重复投递短信的请求在响应超时时重试
+	OtherBind   bool         // 连接池中优先使用其他连接重试, 未完整发送的请求也在其他连接重试
+}
+
+// 设置单个连接的重试策略
+func WithRetry(rp RetryPolicy) Option {
+	return func(cli *Client) {
+		cli.retry = &rp
+	}
+}
+
+// 设置连接池的重试策略, 设置后连接池不再使用每个连接自己的重试策略
+func WithPoolRetry(rp RetryPolicy) PoolOption {
+	return func(p *Pool) {
+		p.retry = &rp
+	}
+}
+
+// submit_sm 与 data_sm 重复发送会重复投递短信
+func isMessage(req pkg.Packer) bool {
+	switch req.(type) {
+	case *pkg.SmppSubmitReqPkt, *pkg.SmppDataReqPkt:
+		return true
+	}
+	return false
+}
+
+// sent 为 false 表示请求未完整发送
+func (rp *RetryPolicy) retryable(req pkg.Packer, sent bool, err error) bool {
+	var se *pkg.StatusError
+	if errors.As(err, &se) {
+		statuses := rp.Statuses
+		if statuses == nil {
+			statuses = DefaultRetryStatuses
+		}
+		for _, s := range statuses {
+			if se.Status == s {
+				return true
+			}
+		}
+		return false
+	}
+	if err == ErrRateLimited || err == context.Canceled || err == context.DeadlineExceeded {
+		return false
+	}
+	if !sent {
+		return rp.OtherBind
+	}
+	return err == ErrResponseTimeout && rp.Timeout && !isMessage(req)
+}
+
+// 重复调用 send 直到成功、不可重试或达到最大次数
+func (rp *RetryPolicy) run(ctx context.Context, req pkg.Packer, send func() (pkg.Packer, bool, error)) (pkg.Packer, error) {
+	backoff := rp.Backoff
+	if backoff == (Backoff{}) {
+		backoff = DefaultBackoff
+	}
+	for attempt := 1; ; attempt++ {
+		resp, sent, err := send()
+		if err == nil || attempt >= rp.MaxAttempts || ctx.Err() != nil || !rp.retryable(req, sent, err) {
+			return resp, err
+		}
+
+		t := time.NewTimer(backoff.Delay(attempt))
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return resp, err
+		}
+	}
+}
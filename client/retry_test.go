@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/boxtsecond/gosmpp/pkg"
+)
+
+func TestRetryable(t *testing.T) {
+	throttled := pkg.NewStatusError(pkg.SMPP_SUBMIT_RESP, pkg.ESME_RTHROTTLED)
+	query := &pkg.SmppQueryReqPkt{MsgID: "1"}
+	tests := []struct {
+		name string
+		rp   RetryPolicy
+		req  pkg.Packer
+		sent bool
+		err  error
+		want bool
+	}{
+		{"default status", RetryPolicy{}, submit("1"), true, throttled, true},
+		{"wrapped status", RetryPolicy{}, submit("1"), true, fmt.Errorf("submit: %w", throttled), true},
+		{"other status", RetryPolicy{}, submit("1"), true, pkg.NewStatusError(pkg.SMPP_SUBMIT_RESP, pkg.ESME_RINVDSTADR), false},
+		{"custom statuses", RetryPolicy{Statuses: []pkg.Status{pkg.ESME_RINVDSTADR}}, submit("1"), true, throttled, false},
+		{"submit timeout", RetryPolicy{Timeout: true}, submit("1"), true, ErrResponseTimeout, false},
+		{"query timeout", RetryPolicy{Timeout: true}, query, true, ErrResponseTimeout, true},
+		{"query timeout disabled", RetryPolicy{}, query, true, ErrResponseTimeout, false},
+		{"connection lost after sending", RetryPolicy{OtherBind: true}, submit("1"), true, errors.New("EOF"), false},
+		{"not sent", RetryPolicy{}, submit("1"), false, ErrNotConnected, false},
+		{"not sent on other bind", RetryPolicy{OtherBind: true}, submit("1"), false, ErrNotConnected, true},
+		{"rate limited", RetryPolicy{OtherBind: true}, submit("1"), false, ErrRateLimited, false},
+		{"deadline", RetryPolicy{OtherBind: true}, submit("1"), false, context.DeadlineExceeded, false},
+	}
+	for _, tt := range tests {
+		if got := tt.rp.retryable(tt.req, tt.sent, tt.err); got != tt.want {
+			t.Errorf("%s: retryable() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRetryRun(t *testing.T) {
+	throttled := pkg.NewStatusError(pkg.SMPP_SUBMIT_RESP, pkg.ESME_RTHROTTLED)
+	tests := []struct {
+		name     string
+		req      pkg.Packer
+		errs     []error // 每次发送的结果, 之后的发送成功
+		attempts int     // 期望的发送次数
+		wantErr  error
+	}{
+		{"success", submit("1"), nil, 1, nil},
+		{"retry then success", submit("1"), []error{throttled, throttled}, 3, nil},
+		{"max attempts", submit("1"), []error{throttled, throttled, throttled, throttled}, 3, throttled},
+		{"not retryable", submit("1"), []error{ErrResponseTimeout}, 1, ErrResponseTimeout},
+		{"query timeout", &pkg.SmppQueryReqPkt{}, []error{ErrResponseTimeout}, 2, nil},
+	}
+
+	rp := RetryPolicy{MaxAttempts: 3, Backoff: Backoff{Min: time.Millisecond}, Timeout: true}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			_, err := rp.run(context.Background(), tt.req, func() (pkg.Packer, bool, error) {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return nil, true, tt.errs[attempts-1]
+				}
+				return &pkg.SmppSubmitRespPkt{}, true, nil
+			})
+			if err != tt.wantErr || attempts != tt.attempts {
+				t.Errorf("run() = %v after %d attempts, want %v after %d", err, attempts, tt.wantErr, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestRetryRunCanceled(t *testing.T) {
+	rp := RetryPolicy{MaxAttempts: 10, Backoff: Backoff{Min: time.Minute}}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	throttled := pkg.NewStatusError(pkg.SMPP_SUBMIT_RESP, pkg.ESME_RTHROTTLED)
+	attempts := 0
+	_, err := rp.run(ctx, submit("1"), func() (pkg.Packer, bool, error) {
+		attempts++
+		return nil, true, throttled
+	})
+	if err != throttled || attempts != 1 {
+		t.Errorf("run() = %v after %d attempts, want %v after 1", err, attempts, throttled)
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	// 前两次返回 ESME_RTHROTTLED
+	var n int32
+	s := newTestSMSC(func(c *pkg.Conn, p pkg.Packer) {
+		if _, ok := p.(*pkg.SmppSubmitReqPkt); ok && atomic.AddInt32(&n, 1) <= 2 {
+			respond(c, p, pkg.ESME_RTHROTTLED)
+			return
+		}
+		acceptAll(c, p)
+	})
+	cli := newTestClient(t, s, WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: Backoff{Min: time.Millisecond}}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if _, err := cli.Submit(ctx, submit("1")); err != nil {
+		t.Errorf("Submit() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&n); got != 3 {
+		t.Errorf("SMSC received %d submit_sm, want 3", got)
+	}
+}
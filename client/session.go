@@ -0,0 +1,323 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/boxtsecond/gosmpp/pkg"
+)
+
+var ErrSessionClosed = errors.New("smpp client: session closed")
+
+type SessionState uint8
+
+const (
+	SESSION_CONNECTING   SessionState = iota // 正在连接并登录
+	SESSION_BOUND                            // 已登录, 可以发送
+	SESSION_DISCONNECTED                     // 连接断开或登录失败, 等待重连
+	SESSION_CLOSED                           // 已调用 Close, 不再重连
+)
+
+func (s SessionState) String() string {
+	switch s {
+	case SESSION_CONNECTING:
+		return "connecting"
+	case SESSION_BOUND:
+		return "bound"
+	case SESSION_DISCONNECTED:
+		return "disconnected"
+	case SESSION_CLOSED:
+		return "closed"
+	}
+	return "unknown"
+}
+
+// 连接断开时等待响应的请求的处理方式
+type InFlightPolicy uint8
+
+const (
+	INFLIGHT_FAIL     InFlightPolicy = iota // 以连接断开的原因结束
+	INFLIGHT_RESUBMIT                       // 重连后重新发送, SMSC 可能已收到原请求, 存在重复发送的风险
+)
+
+// 登录参数, 每次重连使用同样的参数
+type BindParams struct {
+	Addr         string
+	SystemID     string
+	Password     string
+	SystemType   string
+	AddrTON      uint8
+	AddrNPI      uint8
+	AddressRange string
+	Timeout      time.Duration // 连接与等待 bind 响应的超时时间
+}
+
+// 重连间隔的下限, 避免 Min 为 0 时不停地重连
+const MinBackoffDelay = 100 * time.Millisecond
+
+// 指数退避, 第 n 次重连前等待 Min*Factor^(n-1), 不超过 Max, 并随机浮动 ±Jitter
+// 结果不小于 MinBackoffDelay, Factor 小于 1 时按 1 处理
+type Backoff struct {
+	Min    time.Duration
+	Max    time.Duration
+	Factor float64
+	Jitter float64 // 0 ~ 1
+}
+
+var DefaultBackoff = Backoff{Min: time.Second, Max: time.Minute, Factor: 2, Jitter: 0.2}
+
+func (b Backoff) Delay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	factor := b.Factor
+	if factor < 1 {
+		factor = 1
+	}
+	d := float64(b.Min) * math.Pow(factor, float64(attempt-1))
+	if b.Max > 0 && d > float64(b.Max) {
+		d = float64(b.Max)
+	}
+	if b.Jitter > 0 {
+		d += d * b.Jitter * (2*rand.Float64() - 1)
+	}
+	if d < float64(MinBackoffDelay) {
+		d = float64(MinBackoffDelay)
+	}
+	return time.Duration(d)
+}
+
+// 会话状态变化
+type StateEvent struct {
+	State   SessionState
+	Err     error // 断开或登录失败的原因
+	Attempt int   // 连续失败的次数
+}
+
+type SessionOption func(*Session)
+
+// 每次连接创建 Client 时使用的选项
+func WithClientOptions(opts ...Option) SessionOption {
+	return func(s *Session) {
+		s.opts = append(s.opts, opts...)
+	}
+}
+
+func WithBackoff(b Backoff) SessionOption {
+	return func(s *Session) {
+		s.backoff = b
+	}
+}
+
+func WithInFlightPolicy(policy InFlightPolicy) SessionOption {
+	return func(s *Session) {
+		s.policy = policy
+	}
+}
+
+// 状态变化时回调, 在会话的协程中调用, 不应阻塞
+func WithStateChange(f func(StateEvent)) SessionOption {
+	return func(s *Session) {
+		s.onState = f
+	}
+}
+
+// 自动重连的会话, 连接断开后按退避时间重连并以同样的参数重新登录
+type Session struct {
+	ver     uint8
+	bind    BindParams
+	opts    []Option
+	backoff Backoff
+	policy  InFlightPolicy
+	onState func(StateEvent)
+
+	mu      sync.Mutex
+	cli     *Client
+	state   SessionState
+	bound   chan struct{} // 登录成功时关闭
+	closing chan struct{}
+	done    chan struct{}
+	opened  bool
+	once    sync.Once
+
+	closeCtx context.Context // Close 的 ctx, 用于优雅关闭当前连接
+	closeErr error
+}
+
+func NewSession(version uint8, bind BindParams, opts ...SessionOption) *Session {
+	s := &Session{
+		ver:     version,
+		bind:    bind,
+		backoff: DefaultBackoff,
+		state:   SESSION_DISCONNECTED,
+		bound:   make(chan struct{}),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// 启动会话, 立即返回, 之后在后台连接、登录并在断开后重连
+func (s *Session) Open() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.opened {
+		return
+	}
+	s.opened = true
+	go s.run()
+}
+
+// 停止重连并按 Client.Close 优雅关闭当前连接, 返回关闭连接时的错误
+func (s *Session) Close(ctx context.Context) error {
+	s.once.Do(func() {
+		s.closeCtx = ctx
+		close(s.closing)
+	})
+	s.mu.Lock()
+	opened := s.opened
+	s.opened = true // 关闭后不再启动
+	s.mu.Unlock()
+	if !opened {
+		return nil
+	}
+	<-s.done
+	return s.closeErr
+}
+
+func (s *Session) setState(state SessionState, err error, attempt int) {
+	s.mu.Lock()
+	s.state = state
+	s.mu.Unlock()
+	if s.onState != nil {
+		s.onState(StateEvent{State: state, Err: err, Attempt: attempt})
+	}
+}
+
+func (s *Session) State() SessionState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+func (s *Session) run() {
+	defer close(s.done)
+	defer s.setState(SESSION_CLOSED, nil, 0)
+
+	attempt := 0
+	for {
+		s.setState(SESSION_CONNECTING, nil, attempt)
+		cli := NewClient(s.ver, s.opts...)
+		b := s.bind
+		err := cli.Connect(b.Addr, b.SystemID, b.Password, b.SystemType, b.AddrTON, b.AddrNPI, b.AddressRange, b.Timeout)
+		if err == nil {
+			attempt = 0
+			s.mu.Lock()
+			s.cli = cli
+			close(s.bound)
+			s.mu.Unlock()
+			s.setState(SESSION_BOUND, nil, 0)
+
+			closing := false
+			select {
+			case <-cli.Done():
+				err = cli.Err()
+			case <-s.closing:
+				closing = true
+			}
+
+			s.mu.Lock()
+			s.cli = nil
+			s.bound = make(chan struct{})
+			s.mu.Unlock()
+
+			if closing {
+				s.closeErr = cli.Close(s.closeCtx)
+				return
+			}
+		}
+		cli.Disconnect()
+
+		select {
+		case <-s.closing:
+			return
+		default:
+		}
+
+		attempt++
+		s.setState(SESSION_DISCONNECTED, err, attempt)
+		t := time.NewTimer(s.backoff.Delay(attempt))
+		select {
+		case <-t.C:
+		case <-s.closing:
+			t.Stop()
+			return
+		}
+	}
+}
+
+// 已登录且连接未断开时返回当前的 Client, 否则返回 nil
+func (s *Session) healthy() *Client {
+	s.mu.Lock()
+	cli := s.cli
+	s.mu.Unlock()
+	if cli == nil || cli.Err() != nil {
+		return nil
+	}
+	return cli
+}
+
+// 等待登录成功, 返回当前连接的 Client
+func (s *Session) Client(ctx context.Context) (*Client, error) {
+	for {
+		s.mu.Lock()
+		cli, bound := s.cli, s.bound
+		s.mu.Unlock()
+		if cli != nil {
+			return cli, nil
+		}
+
+		select {
+		case <-bound:
+		case <-s.closing:
+			return nil, ErrSessionClosed
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// 发送请求并等待响应, 连接断开时按 InFlightPolicy 处理
+// 未登录时等待登录成功或 ctx 结束
+func (s *Session) Do(ctx context.Context, req pkg.Packer) (pkg.Packer, error) {
+	for {
+		cli, err := s.Client(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := cli.Do(ctx, req)
+		if err == nil || s.policy != INFLIGHT_RESUBMIT || ctx.Err() != nil || cli.Err() == nil {
+			return resp, err
+		}
+	}
+}
+
+// 发送 submit_sm 并等待响应, 连接断开时按 InFlightPolicy 处理
+func (s *Session) Submit(ctx context.Context, p *pkg.SmppSubmitReqPkt) (*pkg.SmppSubmitRespPkt, error) {
+	resp, err := s.Do(ctx, p)
+	if resp == nil {
+		return nil, err
+	}
+	r, ok := resp.(*pkg.SmppSubmitRespPkt)
+	if !ok {
+		return nil, ErrRespNotMatch
+	}
+	return r, err
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/boxtsecond/gosmpp/pkg"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       Backoff
+		attempt int
+		want    time.Duration
+	}{
+		{"first attempt", Backoff{Min: time.Second, Max: time.Minute, Factor: 2}, 1, time.Second},
+		{"attempt below 1", Backoff{Min: time.Second, Max: time.Minute, Factor: 2}, 0, time.Second},
+		{"exponential", Backoff{Min: time.Second, Max: time.Minute, Factor: 2}, 4, 8 * time.Second},
+		{"capped by max", Backoff{Min: time.Second, Max: 5 * time.Second, Factor: 2}, 10, 5 * time.Second},
+		{"no max", Backoff{Min: time.Second, Factor: 3}, 3, 9 * time.Second},
+		{"factor below 1", Backoff{Min: time.Second, Max: time.Minute, Factor: 0.5}, 5, time.Second},
+		{"zero min", Backoff{}, 3, MinBackoffDelay},
+		{"below minimum", Backoff{Min: time.Millisecond, Factor: 2}, 2, MinBackoffDelay},
+	}
+	for _, tt := range tests {
+		if got := tt.b.Delay(tt.attempt); got != tt.want {
+			t.Errorf("%s: Delay(%d) = %v, want %v", tt.name, tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	b := Backoff{Min: time.Second, Max: time.Minute, Factor: 2, Jitter: 0.2}
+	for i := 0; i < 100; i++ {
+		d := b.Delay(2)
+		if d < 1600*time.Millisecond || d > 2400*time.Millisecond {
+			t.Fatalf("Delay(2) = %v, want 2s ±20%%", d)
+		}
+	}
+	b = Backoff{Min: MinBackoffDelay, Jitter: 1}
+	for i := 0; i < 100; i++ {
+		if d := b.Delay(1); d < MinBackoffDelay {
+			t.Fatalf("Delay(1) = %v, want at least %v", d, MinBackoffDelay)
+		}
+	}
+}
+
+func newTestSession(t *testing.T, s *testSMSC, opts ...SessionOption) *Session {
+	t.Helper()
+	opts = append([]SessionOption{
+		WithClientOptions(WithDialer(s.dial), WithErrorLog(discardLog)),
+		WithBackoff(Backoff{Min: MinBackoffDelay}),
+	}, opts...)
+	sess := NewSession(pkg.VERSION, testBind(), opts...)
+	sess.Open()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		sess.Close(ctx)
+	})
+	return sess
+}
+
+func TestSessionReconnect(t *testing.T) {
+	s := newTestSMSC(nil)
+	var (
+		mu     sync.Mutex
+		states []SessionState
+	)
+	sess := newTestSession(t, s, WithStateChange(func(e StateEvent) {
+		mu.Lock()
+		states = append(states, e.State)
+		mu.Unlock()
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if _, err := sess.Submit(ctx, submit("1")); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	// SMSC 断开连接后重新登录
+	s.last().Close()
+	if !eventually(t, time.Second, func() bool { return s.bound() == 2 && sess.State() == SESSION_BOUND }) {
+		t.Fatalf("session did not rebind, state %v, %d binds", sess.State(), s.bound())
+	}
+	if _, err := sess.Submit(ctx, submit("2")); err != nil {
+		t.Fatalf("Submit() after reconnect error = %v", err)
+	}
+
+	if err := sess.Close(ctx); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if sess.State() != SESSION_CLOSED {
+		t.Errorf("State() = %v, want %v", sess.State(), SESSION_CLOSED)
+	}
+	if _, err := sess.Client(ctx); err != ErrSessionClosed {
+		t.Errorf("Client() after Close error = %v, want %v", err, ErrSessionClosed)
+	}
+
+	want := []SessionState{SESSION_CONNECTING, SESSION_BOUND, SESSION_DISCONNECTED, SESSION_CONNECTING, SESSION_BOUND, SESSION_CLOSED}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(states) != len(want) {
+		t.Fatalf("states = %v, want %v", states, want)
+	}
+	for i := range want {
+		if states[i] != want[i] {
+			t.Fatalf("states = %v, want %v", states, want)
+		}
+	}
+}
+
+func TestSessionInFlightPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  InFlightPolicy
+		wantErr bool
+	}{
+		{"fail", INFLIGHT_FAIL, true},
+		{"resubmit", INFLIGHT_RESUBMIT, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 第一个连接收到 submit_sm 后断开, 之后正常响应
+			var (
+				mu    sync.Mutex
+				first = true
+			)
+			s := newTestSMSC(func(c *pkg.Conn, p pkg.Packer) {
+				if _, ok := p.(*pkg.SmppSubmitReqPkt); ok {
+					mu.Lock()
+					drop := first
+					first = false
+					mu.Unlock()
+					if drop {
+						c.Close()
+						return
+					}
+				}
+				acceptAll(c, p)
+			})
+			sess := newTestSession(t, s, WithInFlightPolicy(tt.policy))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			_, err := sess.Submit(ctx, submit("1"))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Submit() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
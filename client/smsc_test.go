@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"log"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/boxtsecond/gosmpp/pkg"
+)
+
+// 内存中的 SMSC, 通过 net.Pipe 与 Client 相连
+// 登录总是成功, 其他 PDU 交给 handle 在读取协程中处理
+type testSMSC struct {
+	handle func(c *pkg.Conn, p pkg.Packer)
+	tls    *tls.Config // 不为 nil 时以 TLS 服务端接受连接
+
+	mu    sync.Mutex
+	conns []*pkg.Conn
+}
+
+func newTestSMSC(handle func(c *pkg.Conn, p pkg.Packer)) *testSMSC {
+	if handle == nil {
+		handle = acceptAll
+	}
+	return &testSMSC{handle: handle}
+}
+
+func (s *testSMSC) dial(ctx context.Context, addr string) (net.Conn, error) {
+	client, server := net.Pipe()
+	if s.tls != nil {
+		server = tls.Server(server, s.tls)
+	}
+	c := pkg.NewConnection(server, pkg.VERSION)
+	c.SetState(pkg.CONNECTION_CONNECTED)
+	go s.serve(c)
+	return client, nil
+}
+
+func (s *testSMSC) serve(c *pkg.Conn) {
+	defer c.Close()
+	for {
+		p, err := c.RecvAndUnpackPkt(0)
+		if err != nil {
+			return
+		}
+		if b, ok := p.(*pkg.SmppBindTransceiverReqPkt); ok {
+			s.mu.Lock()
+			s.conns = append(s.conns, c)
+			s.mu.Unlock()
+			c.SendPkt(&pkg.SmppBindTransceiverRespPkt{
+				SystemID:           "smsc",
+				ScInterfaceVersion: pkg.NewTLV(pkg.TAG_SCInterfaceVersion, []byte{pkg.VERSION}),
+			}, b.SequenceNum)
+			continue
+		}
+		s.handle(c, p)
+	}
+}
+
+// 已登录的连接数
+func (s *testSMSC) bound() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// 最近登录的连接
+func (s *testSMSC) last() *pkg.Conn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.conns) == 0 {
+		return nil
+	}
+	return s.conns[len(s.conns)-1]
+}
+
+// 以 ESME_ROK 响应所有请求, submit_sm 的 message_id 为目的号码
+func acceptAll(c *pkg.Conn, p pkg.Packer) {
+	respond(c, p, pkg.ESME_ROK)
+}
+
+func respond(c *pkg.Conn, p pkg.Packer, status pkg.Status) {
+	switch r := p.(type) {
+	case *pkg.SmppSubmitReqPkt:
+		c.SendPkt(&pkg.SmppSubmitRespPkt{MsgID: r.DestinationAddr, Status: status}, r.SequenceNum)
+	case *pkg.SmppDataReqPkt:
+		c.SendPkt(&pkg.SmppDataRespPkt{MsgID: r.DestinationAddr, Status: status}, r.SequenceNum)
+	case *pkg.SmppQueryReqPkt:
+		c.SendPkt(&pkg.SmppQueryRespPkt{MsgID: r.MsgID, Status: status}, r.SequenceNum)
+	case *pkg.SmppCancelReqPkt:
+		c.SendPkt(&pkg.SmppCancelRespPkt{Status: status}, r.SequenceNum)
+	case *pkg.SmppEnquireLinkReqPkt:
+		c.SendPkt(&pkg.SmppEnquireLinkRespPkt{}, r.SequenceNum)
+	case *pkg.SmppUnbindReqPkt:
+		c.SendPkt(&pkg.SmppUnbindRespPkt{}, r.SequenceNum)
+	}
+}
+
+var discardLog = log.New(ioutil.Discard, "", 0)
+
+func newTestClient(t *testing.T, s *testSMSC, opts ...Option) *Client {
+	t.Helper()
+	opts = append([]Option{WithDialer(s.dial), WithErrorLog(discardLog)}, opts...)
+	cli := NewClient(pkg.VERSION, opts...)
+	if err := cli.Connect("smsc:2775", "id", "pw", "", 0, 0, "", time.Second); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(cli.Disconnect)
+	return cli
+}
+
+func testBind() BindParams {
+	return BindParams{Addr: "smsc:2775", SystemID: "id", Password: "pw", Timeout: time.Second}
+}
+
+func submit(dest string) *pkg.SmppSubmitReqPkt {
+	return &pkg.SmppSubmitReqPkt{DestinationAddr: dest, ShortMessage: "hi", SmLength: 2}
+}
+
+// 在 d 内等待 cond 成立
+func eventually(t *testing.T, d time.Duration, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(d)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return cond()
+}
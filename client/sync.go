@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+
+	"github.com/boxtsecond/gosmpp/pkg"
+)
+
+// 发送请求并等待响应, 对端返回 generic_nack 或非 ESME_ROK 的 command_status 时返回 *pkg.StatusError
+// 设置了 RetryPolicy 时按策略重试, 可以在多个协程中同时调用
+func (cli *Client) Do(ctx context.Context, req pkg.Packer) (pkg.Packer, error) {
+	if cli.retry == nil {
+		resp, _, err := cli.do(ctx, req)
+		return resp, err
+	}
+	return cli.retry.run(ctx, req, func() (pkg.Packer, bool, error) {
+		return cli.do(ctx, req)
+	})
+}
+
+// 发送一次请求并等待响应, sent 表示请求是否已完整发送
+func (cli *Client) do(ctx context.Context, req pkg.Packer) (resp pkg.Packer, sent bool, err error) {
+	f, err := cli.SendAsync(ctx, req, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	resp, err = f.Wait(ctx)
+	if err != nil {
+		return nil, true, err
+	}
+
+	cmd, status := responseStatus(resp)
+	if cmd == pkg.SMPP_GENERIC_NACK {
+		return nil, true, pkg.NewStatusError(cmd, status)
+	}
+	if status != pkg.ESME_ROK {
+		return resp, true, pkg.NewStatusError(cmd, status)
+	}
+	return resp, true, nil
+}
+
+// 发送 submit_sm 并等待响应
+func (cli *Client) Submit(ctx context.Context, p *pkg.SmppSubmitReqPkt) (*pkg.SmppSubmitRespPkt, error) {
+	resp, err := cli.Do(ctx, p)
+	if resp == nil {
+		return nil, err
+	}
+	r, ok := resp.(*pkg.SmppSubmitRespPkt)
+	if !ok {
+		return nil, ErrRespNotMatch
+	}
+	return r, err
+}
+
+// 发送 data_sm 并等待响应
+func (cli *Client) DataSM(ctx context.Context, p *pkg.SmppDataReqPkt) (*pkg.SmppDataRespPkt, error) {
+	resp, err := cli.Do(ctx, p)
+	if resp == nil {
+		return nil, err
+	}
+	r, ok := resp.(*pkg.SmppDataRespPkt)
+	if !ok {
+		return nil, ErrRespNotMatch
+	}
+	return r, err
+}
+
+// 发送 query_sm 并等待响应
+func (cli *Client) QuerySM(ctx context.Context, p *pkg.SmppQueryReqPkt) (*pkg.SmppQueryRespPkt, error) {
+	resp, err := cli.Do(ctx, p)
+	if resp == nil {
+		return nil, err
+	}
+	r, ok := resp.(*pkg.SmppQueryRespPkt)
+	if !ok {
+		return nil, ErrRespNotMatch
+	}
+	return r, err
+}
+
+// 发送 cancel_sm 并等待响应
+func (cli *Client) CancelSM(ctx context.Context, p *pkg.SmppCancelReqPkt) (*pkg.SmppCancelRespPkt, error) {
+	resp, err := cli.Do(ctx, p)
+	if resp == nil {
+		return nil, err
+	}
+	r, ok := resp.(*pkg.SmppCancelRespPkt)
+	if !ok {
+		return nil, ErrRespNotMatch
+	}
+	return r, err
+}
+
+// 按该 SMSC 的编码习惯编译短信并依次发送, 返回每个 PDU 的响应
+// 某一条失败时停止发送, 返回已收到的响应与错误
+func (cli *Client) SubmitMessage(ctx context.Context, m *pkg.Message) ([]pkg.Packer, error) {
+	packets, err := cli.GetMessagePkgs(m)
+	if err != nil {
+		return nil, err
+	}
+	resps := make([]pkg.Packer, 0, len(packets))
+	for _, p := range packets {
+		resp, err := cli.Do(ctx, p)
+		if err != nil {
+			return resps, err
+		}
+		resps = append(resps, resp)
+	}
+	return resps, nil
+}
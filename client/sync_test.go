@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boxtsecond/gosmpp/pkg"
+)
+
+func TestDoStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		handle  func(c *pkg.Conn, p pkg.Packer)
+		resp    bool
+		wantCmd pkg.CommandID
+		status  pkg.Status
+	}{
+		{"ok", acceptAll, true, 0, pkg.ESME_ROK},
+		{"error status", func(c *pkg.Conn, p pkg.Packer) { respond(c, p, pkg.ESME_RSYSERR) }, true, pkg.SMPP_SUBMIT_RESP, pkg.ESME_RSYSERR},
+		{"generic_nack", func(c *pkg.Conn, p pkg.Packer) {
+			if r, ok := p.(*pkg.SmppSubmitReqPkt); ok {
+				c.SendPkt(&pkg.SmppGenericNackReqPkt{Status: pkg.ESME_RINVCMDLEN}, r.SequenceNum)
+			}
+		}, false, pkg.SMPP_GENERIC_NACK, pkg.ESME_RINVCMDLEN},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cli := newTestClient(t, newTestSMSC(tt.handle))
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			resp, err := cli.Submit(ctx, submit("1"))
+			if (resp != nil) != tt.resp {
+				t.Errorf("Submit() resp = %v, want resp %v", resp, tt.resp)
+			}
+			if tt.status == pkg.ESME_ROK {
+				if err != nil {
+					t.Errorf("Submit() error = %v", err)
+				}
+				return
+			}
+			se, ok := err.(*pkg.StatusError)
+			if !ok {
+				t.Fatalf("Submit() error = %v, want *pkg.StatusError", err)
+			}
+			if se.Command != tt.wantCmd || se.Status != tt.status {
+				t.Errorf("Submit() error = %v, want %s %s", se, tt.wantCmd, tt.status)
+			}
+		})
+	}
+}
+
+func TestTypedRequests(t *testing.T) {
+	cli := newTestClient(t, newTestSMSC(nil))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if r, err := cli.DataSM(ctx, &pkg.SmppDataReqPkt{DestinationAddr: "2"}); err != nil || r.MsgID != "2" {
+		t.Errorf("DataSM() = %+v, %v", r, err)
+	}
+	if r, err := cli.QuerySM(ctx, &pkg.SmppQueryReqPkt{MsgID: "3"}); err != nil || r.MsgID != "3" {
+		t.Errorf("QuerySM() = %+v, %v", r, err)
+	}
+	if _, err := cli.CancelSM(ctx, &pkg.SmppCancelReqPkt{MsgID: "4"}); err != nil {
+		t.Errorf("CancelSM() error = %v", err)
+	}
+}
+
+func TestSubmitMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		fail   int // 第几个 submit_sm 返回错误, 0 表示不返回
+		resps  int
+		hasErr bool
+	}{
+		{"all accepted", 0, 3, false},
+		{"stops at the first error", 2, 1, true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var n int
+			s := newTestSMSC(func(c *pkg.Conn, p pkg.Packer) {
+				if _, ok := p.(*pkg.SmppSubmitReqPkt); ok {
+					n++
+					if n == tt.fail {
+						respond(c, p, pkg.ESME_RSYSERR)
+						return
+					}
+				}
+				acceptAll(c, p)
+			})
+			cli := newTestClient(t, s)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			resps, err := cli.SubmitMessage(ctx, &pkg.Message{DestinationAddr: "1", Text: strings.Repeat("a", 400)})
+			if (err != nil) != tt.hasErr || len(resps) != tt.resps {
+				t.Errorf("SubmitMessage() = %d responses, %v, want %d responses, error %v", len(resps), err, tt.resps, tt.hasErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/boxtsecond/gosmpp/pkg"
+)
+
+// 测试用的证书, 由 ca 签发; ca 为 nil 时自签名
+func testCert(t *testing.T, cn string, ca *tls.Certificate) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     []string{cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	parent, signer := tmpl, interface{}(key)
+	if ca == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+	} else {
+		parent, signer = ca.Leaf, ca.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestTLS(t *testing.T) {
+	ca := testCert(t, "test ca", nil)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+	serverCert := testCert(t, "smsc", &ca)
+	clientCert := testCert(t, "esme", &ca)
+
+	tests := []struct {
+		name    string
+		client  *tls.Config
+		wantErr bool
+		peer    string // SMSC 看到的客户端证书
+	}{
+		{"server auth", &tls.Config{RootCAs: pool}, false, ""},
+		{"mutual auth", &tls.Config{RootCAs: pool, Certificates: []tls.Certificate{clientCert}}, false, "esme"},
+		{"unknown ca", &tls.Config{}, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peers := make(chan string, 1)
+			s := newTestSMSC(func(c *pkg.Conn, p pkg.Packer) {
+				if _, ok := p.(*pkg.SmppSubmitReqPkt); ok {
+					state, _ := c.TLSConnectionState()
+					cn := ""
+					if len(state.PeerCertificates) > 0 {
+						cn = state.PeerCertificates[0].Subject.CommonName
+					}
+					peers <- cn
+				}
+				acceptAll(c, p)
+			})
+			s.tls = &tls.Config{
+				Certificates: []tls.Certificate{serverCert},
+				ClientAuth:   tls.VerifyClientCertIfGiven,
+				ClientCAs:    pool,
+			}
+
+			cli := NewClient(pkg.VERSION, WithDialer(s.dial), WithTLS(tt.client), WithErrorLog(discardLog))
+			err := cli.Connect("smsc:2775", "id", "pw", "", 0, 0, "", time.Second)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Connect() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer cli.Disconnect()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			if _, err := cli.Submit(ctx, submit("1")); err != nil {
+				t.Fatalf("Submit() error = %v", err)
+			}
+			if peer := <-peers; peer != tt.peer {
+				t.Errorf("peer certificate %q, want %q", peer, tt.peer)
+			}
+		})
+	}
+}
@@ -1,8 +1,9 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
-	"fmt"
 	"log"
 	"sync"
 	"time"
@@ -22,119 +23,60 @@ var (
 )
 
 func startAClient(idx int) {
-	c := client.NewClient(pkg.VERSION)
 	defer wg.Done()
-	defer c.Disconnect()
 
-	fmt.Println("============")
-	fmt.Println("addr: ", *addr)
-	fmt.Println("systemID: ", *systemID)
-	fmt.Println("password: ", *password)
-	fmt.Println("systemType: ", *systemType)
-	fmt.Println("phone: ", *phone)
-	err := c.Connect(*addr, *systemID, *password, *systemType, 0, 0, "", 3*time.Second)
+	// SMSC 发来的请求
+	handler := func(req pkg.Packer) pkg.Packer {
+		switch p := req.(type) {
+		case *pkg.SmppDeliverReqPkt:
+			log.Printf("client %d: receive a smpp deliver request: \n%v", idx, p)
+			if p.EsmClass == pkg.SM_DELIVER {
+				log.Printf("client %d: the smpp deliver request is a status report.", idx)
+			}
+			return &pkg.SmppDeliverRespPkt{Status: pkg.ESME_ROK}
+		}
+		return nil
+	}
 
+	c := client.NewClient(pkg.VERSION, client.WithHandler(handler))
+
+	err := c.Connect(*addr, *systemID, *password, *systemType, 0, 0, "", 3*time.Second)
 	if err != nil {
 		log.Printf("client %d: connect error: %s.", idx, err)
 		return
 	}
 	log.Printf("client %d: connect and auth ok", idx)
-
-	t := time.NewTicker(time.Second)
-	defer t.Stop()
-	maxSubmit := 1
-	count := 0
-
-	go func() {
-		for {
-			// recv packets
-			i, err := c.RecvAndUnpackPkt(0)
-			if err != nil {
-				log.Printf("client %d: client read and unpack pkt error: %s.", idx, err)
-				return
-			}
-
-			switch p := i.(type) {
-			case *pkg.SmppSubmitRespPkt:
-				log.Printf("client %d: receive a smpp submit response: \n%v", idx, p)
-
-			case *pkg.SmppDeliverReqPkt:
-				log.Printf("client %d: receive a smpp deliver request: \n%v", idx, p)
-				if p.EsmClass == pkg.SM_DELIVER {
-					log.Printf("client %d: the smpp deliver request: %s is a status report.", idx, p)
-				}
-				rsp := &pkg.SmppDeliverRespPkt{
-					Status: pkg.Status(0),
-				}
-
-				err := c.SendRspPkt(rsp, p.SequenceNum)
-				if err != nil {
-					log.Printf("client %d: send smpp deliver response error: %s.", idx, err)
-					break
-				} else {
-					log.Printf("client %d: send smpp deliver response ok.", idx)
-				}
-
-			case *pkg.SmppEnquireLinkReqPkt:
-				log.Printf("client %d: receive a smpp active request.", idx)
-				rsp := &pkg.SmppEnquireLinkRespPkt{}
-				err := c.SendRspPkt(rsp, p.SequenceNum)
-				if err != nil {
-					log.Printf("client %d: send smpp active response error: %s.", idx, err)
-					break
-				}
-			case *pkg.SmppEnquireLinkRespPkt:
-				log.Printf("client %d: receive a smpp active response.", idx)
-
-			case *pkg.SmppUnbindReqPkt:
-				log.Printf("client %d: receive a smpp unbind request.", idx)
-				rsp := &pkg.SmppUnbindRespPkt{}
-				err := c.SendRspPkt(rsp, p.SequenceNum)
-				if err != nil {
-					log.Printf("client %d: send smpp unbind response error: %s.", idx, err)
-					break
-				}
-			case *pkg.SmppUnbindRespPkt:
-				log.Printf("client %d: receive a smpp unbind response.", idx)
-			}
+	defer func() {
+		// 等待未收到的响应, 发送 unbind 后断开
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if err := c.Close(ctx); err != nil {
+			log.Printf("client %d: close error: %s.", idx, err)
 		}
 	}()
 
-	for {
-		select {
-		case <-t.C:
-			if count >= maxSubmit {
-				continue
-			}
-
-			p := &pkg.SmppSubmitReqPkt{
-				DestinationAddr:    *phone,
-				EsmClass:           0,
-				PriorityFlag:       pkg.NORMAL_PRIORITY,
-				RegisteredDelivery: pkg.NEED_REPORT,
-				DataCoding:         pkg.ASCII,
-				ShortMessage:       *msg,
-			}
-
-			pkgs, err := pkg.GetMsgPkgs(p)
-			if err != nil {
-				log.Printf("client %d: get long msg pkg error: %s.", idx, err)
-				continue
-			}
-
-			for _, req := range pkgs {
-				_, err = c.SendReqPkt(req)
-			}
-			if err != nil {
-				log.Printf("client %d: send a smpp submit request error: %s.", idx, err)
-				return
-			} else {
-				log.Printf("client %d: send a smpp submit request ok", idx)
-			}
-			count += 1
-		default:
-		}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resps, err := c.SubmitMessage(ctx, &pkg.Message{
+		DestinationAddr:    *phone,
+		Text:               *msg,
+		RegisteredDelivery: pkg.NEED_REPORT,
+	})
+	var se *pkg.StatusError
+	if errors.As(err, &se) {
+		log.Printf("client %d: submit rejected by smsc: %s.", idx, se.Status)
+		return
+	} else if err != nil {
+		log.Printf("client %d: send a smpp submit request error: %s.", idx, err)
+		return
+	}
+	for _, rsp := range resps {
+		log.Printf("client %d: receive a smpp submit response: \n%v", idx, rsp)
 	}
+
+	// 等待状态报告
+	time.Sleep(5 * time.Second)
 }
 
 var wg sync.WaitGroup
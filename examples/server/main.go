@@ -74,7 +74,7 @@ func handleSubmit(r *server.Response, p *server.Packet) (bool, error) {
 		DataCoding:         pkg.ASCII,
 		EsmClass:           pkg.SM_DELIVER,
 		SmLength:           uint8(len(msgContent)),
-		ShortMessage:       msgContent,
+		ShortMessage:       string(msgContent),
 		SequenceNum:        <-p.Conn.SequenceNum,
 	})
 	go mockDeliver(deliverPkgs, p)
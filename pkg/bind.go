@@ -56,9 +56,9 @@ func (p *SmppBindTransceiverReqPkt) Unpack(data []byte) error {
 	p.SystemID = string(r.ReadOCString(16))
 	p.Password = string(r.ReadOCString(9))
 	p.SystemType = string(r.ReadOCString(13))
-	p.InterfaceVersion = r.ReadByte()
-	p.AddrTON = r.ReadByte()
-	p.AddrNPI = r.ReadByte()
+	p.InterfaceVersion = r.ReadUint8()
+	p.AddrTON = r.ReadUint8()
+	p.AddrNPI = r.ReadUint8()
 	p.AddressRange = string(r.ReadOCString(16))
 	return r.Error()
 }
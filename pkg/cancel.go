@@ -0,0 +1,117 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+)
+
+const (
+	SmppCancelReqPktLen  = HeaderPktLen + 4
+	SmppCancelRespPktLen = HeaderPktLen
+)
+
+type SmppCancelReqPkt struct {
+	ServiceType     string // 为空时按 MsgID 取消, 否则取消该服务类型下的所有短消息
+	MsgID           string // 为空时按地址与服务类型取消
+	SourceAddrTON   uint8  // 源地址编码类型
+	SourceAddrNPI   uint8  // 源地址编码方案
+	SourceAddr      string // 提交该短消息的SME的地址
+	DestAddrTON     uint8  // 目的地址编码类型
+	DestAddrNPI     uint8  // 目的地址编码方案
+	DestinationAddr string // 短消息的目的地址
+
+	// used in session
+	SequenceNum uint32
+}
+
+func (p *SmppCancelReqPkt) Pack(seqId uint32) ([]byte, error) {
+	serviceType := NewCOctetString(p.ServiceType).Byte(6)
+	msgId := NewCOctetString(p.MsgID).Byte(65)
+	sourceAddr := NewCOctetString(p.SourceAddr).Byte(21)
+	destinationAddr := NewCOctetString(p.DestinationAddr).Byte(21)
+	var commandLength = SmppCancelReqPktLen + uint32(len(serviceType)+len(msgId)+len(sourceAddr)+len(destinationAddr))
+
+	var w = newPkgWriter(commandLength)
+	// header
+	header := Header{
+		CommandLength: commandLength,
+		CommandID:     uint32(SMPP_CANCEL),
+		SequenceNum:   seqId,
+	}
+	w.WriteHeader(header)
+	p.SequenceNum = seqId
+
+	// body
+	w.WriteBytes(serviceType)
+	w.WriteBytes(msgId)
+	w.WriteByte(p.SourceAddrTON)
+	w.WriteByte(p.SourceAddrNPI)
+	w.WriteBytes(sourceAddr)
+	w.WriteByte(p.DestAddrTON)
+	w.WriteByte(p.DestAddrNPI)
+	w.WriteBytes(destinationAddr)
+
+	return w.Bytes()
+}
+
+func (p *SmppCancelReqPkt) Unpack(data []byte) error {
+	var r = newPkgReader(data)
+
+	p.ServiceType = string(r.ReadOCString(6))
+	p.MsgID = string(r.ReadOCString(65))
+	p.SourceAddrTON = r.ReadUint8()
+	p.SourceAddrNPI = r.ReadUint8()
+	p.SourceAddr = string(r.ReadOCString(21))
+	p.DestAddrTON = r.ReadUint8()
+	p.DestAddrNPI = r.ReadUint8()
+	p.DestinationAddr = string(r.ReadOCString(21))
+
+	return r.Error()
+}
+
+func (p *SmppCancelReqPkt) String() string {
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "--- SMPP Cancel Req ---")
+	fmt.Fprintln(&b, "ServiceType: ", p.ServiceType)
+	fmt.Fprintln(&b, "MsgID: ", p.MsgID)
+	fmt.Fprintln(&b, "SourceAddrTON: ", p.SourceAddrTON)
+	fmt.Fprintln(&b, "SourceAddrNPI: ", p.SourceAddrNPI)
+	fmt.Fprintln(&b, "SourceAddr: ", p.SourceAddr)
+	fmt.Fprintln(&b, "DestAddrTON: ", p.DestAddrTON)
+	fmt.Fprintln(&b, "DestAddrNPI: ", p.DestAddrNPI)
+	fmt.Fprintln(&b, "DestinationAddr: ", p.DestinationAddr)
+	return b.String()
+}
+
+type SmppCancelRespPkt struct {
+	// used in session
+	Status      Status
+	SequenceNum uint32
+}
+
+func (p *SmppCancelRespPkt) Pack(seqId uint32) ([]byte, error) {
+	var w = newPkgWriter(SmppCancelRespPktLen)
+
+	// header
+	header := Header{
+		CommandLength: SmppCancelRespPktLen,
+		CommandID:     uint32(SMPP_CANCEL_RESP),
+		CommandStatus: uint32(p.Status),
+		SequenceNum:   seqId,
+	}
+	w.WriteHeader(header)
+	p.SequenceNum = seqId
+
+	return w.Bytes()
+}
+
+func (p *SmppCancelRespPkt) Unpack(data []byte) error {
+	return nil
+}
+
+func (p *SmppCancelRespPkt) String() string {
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "--- SMPP Cancel Resp ---")
+	fmt.Fprintln(&b, "Status: ", p.Status)
+	return b.String()
+}
@@ -0,0 +1,139 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// data_coding 对应的编解码, Encode 的输入与 Decode 的输出均为 UTF-8 文本
+type Codec interface {
+	Encode(text string) ([]byte, error)
+	Decode(b []byte) (string, error)
+}
+
+type gsm7Codec struct{}
+
+func (gsm7Codec) Encode(text string) ([]byte, error) {
+	return Encode7Bit(text)
+}
+
+func (gsm7Codec) Decode(b []byte) (string, error) {
+	return Decode7Bit(b)
+}
+
+// IA5 (CCITT T.50), 即 7 位 ASCII
+type ia5Codec struct{}
+
+func (ia5Codec) Encode(text string) ([]byte, error) {
+	b := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r >= 0x80 {
+			return nil, fmt.Errorf("ia5: invalid character %q", r)
+		}
+		b = append(b, byte(r))
+	}
+	return b, nil
+}
+
+func (ia5Codec) Decode(b []byte) (string, error) {
+	for _, c := range b {
+		if c >= 0x80 {
+			return "", fmt.Errorf("ia5: invalid byte 0x%02x", c)
+		}
+	}
+	return string(b), nil
+}
+
+// 二进制内容原样传输
+type octetCodec struct{}
+
+func (octetCodec) Encode(text string) ([]byte, error) {
+	return []byte(text), nil
+}
+
+func (octetCodec) Decode(b []byte) (string, error) {
+	return string(b), nil
+}
+
+type textCodec struct {
+	enc encoding.Encoding
+}
+
+func (c textCodec) Encode(text string) ([]byte, error) {
+	if !utf8.ValidString(text) {
+		return nil, errors.New("invalid utf8 runes")
+	}
+	b, _, err := transform.Bytes(c.enc.NewEncoder(), []byte(text))
+	return b, err
+}
+
+func (c textCodec) Decode(b []byte) (string, error) {
+	out, _, err := transform.Bytes(c.enc.NewDecoder(), b)
+	return string(out), err
+}
+
+// JIS 与 PICTOGRAM 按 Shift_JIS 解释, 这是日本运营商 SMSC 的常见做法 (绘文字在 Shift_JIS 的用户定义区),
+// 拆分时也按 Shift_JIS 计算字符边界, 不同时请用 RegisterCodec 替换
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[uint8]Codec{
+		ASCII:          gsm7Codec{},
+		IA5:            ia5Codec{},
+		OCTET:          octetCodec{},
+		LATIN1:         textCodec{charmap.ISO8859_1},
+		BINARY:         octetCodec{},
+		JIS:            textCodec{japanese.ShiftJIS},
+		PICTOGRAM:      textCodec{japanese.ShiftJIS},
+		CYRILLIC:       textCodec{charmap.ISO8859_5},
+		HEBREW:         textCodec{charmap.ISO8859_8},
+		UCS2:           textCodec{unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)},
+		ISO2022JP:      textCodec{japanese.ISO2022JP},
+		EXTENDED_KANJI: textCodec{japanese.EUCJP},
+		KSC5601:        textCodec{korean.EUCKR},
+		GB18030:        textCodec{simplifiedchinese.GB18030},
+	}
+)
+
+// 注册或替换 dataCoding 对应的编解码
+func RegisterCodec(dataCoding uint8, c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[dataCoding] = c
+}
+
+// 未单独注册的 GSM 编码组按其字符集查找
+func GetCodec(dataCoding uint8) (Codec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	c, ok := codecs[dataCoding]
+	if !ok {
+		c, ok = codecs[DCS(dataCoding).DataCoding()]
+	}
+	return c, ok
+}
+
+func EncodeContent(dataCoding uint8, text string) ([]byte, error) {
+	c, ok := GetCodec(dataCoding)
+	if !ok {
+		return nil, fmt.Errorf("data_coding: met an unexpected data_coding [%d]", dataCoding)
+	}
+	return c.Encode(text)
+}
+
+func DecodeContent(dataCoding uint8, b []byte) (string, error) {
+	c, ok := GetCodec(dataCoding)
+	if !ok {
+		return "", fmt.Errorf("data_coding: met an unexpected data_coding [%d]", dataCoding)
+	}
+	return c.Decode(b)
+}
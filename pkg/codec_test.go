@@ -0,0 +1,107 @@
+package pkg
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		dataCoding uint8
+		text       string
+		want       []byte // 为 nil 时只检查往返
+	}{
+		{"gsm7", ASCII, "Hello @£$", []byte{'H', 'e', 'l', 'l', 'o', ' ', 0x00, 0x01, 0x02}},
+		{"ia5", IA5, "Hello", []byte("Hello")},
+		{"octet", OCTET, "\x00\xff", []byte{0x00, 0xFF}},
+		{"binary", BINARY, "\x01\x02", []byte{0x01, 0x02}},
+		{"latin1", LATIN1, "café", []byte{'c', 'a', 'f', 0xE9}},
+		{"jis as shift_jis", JIS, "日本", []byte{0x93, 0xFA, 0x96, 0x7B}},
+		{"pictogram as shift_jis", PICTOGRAM, "日本", []byte{0x93, 0xFA, 0x96, 0x7B}},
+		{"cyrillic", CYRILLIC, "Привет", nil},
+		{"hebrew", HEBREW, "שלום", nil},
+		{"ucs2", UCS2, "你好", []byte{0x4F, 0x60, 0x59, 0x7D}},
+		{"ucs2 surrogate pair", UCS2, "😀", []byte{0xD8, 0x3D, 0xDE, 0x00}},
+		{"iso-2022-jp", ISO2022JP, "日本", nil},
+		{"extended kanji", EXTENDED_KANJI, "日本", nil},
+		{"ksc5601", KSC5601, "한국", nil},
+		{"gb18030", GB18030, "中文", []byte{0xD6, 0xD0, 0xCE, 0xC4}},
+		{"gsm general group ucs2", 0x18, "你好", []byte{0x4F, 0x60, 0x59, 0x7D}},
+		{"gsm message class 8 bit", 0xF4, "\x01", []byte{0x01}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := EncodeContent(tt.dataCoding, tt.text)
+			if err != nil {
+				t.Fatalf("EncodeContent() error = %v", err)
+			}
+			if tt.want != nil && !bytes.Equal(b, tt.want) {
+				t.Errorf("EncodeContent() = %x, want %x", b, tt.want)
+			}
+			text, err := DecodeContent(tt.dataCoding, b)
+			if err != nil || text != tt.text {
+				t.Errorf("DecodeContent() = %q, %v, want %q", text, err, tt.text)
+			}
+		})
+	}
+}
+
+func TestCodecErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		dataCoding uint8
+		text       string
+	}{
+		{"ia5 rejects non ascii", IA5, "é"},
+		{"gsm7 rejects chinese", ASCII, "你"},
+		{"reserved smpp data_coding", 0x0B, "x"},
+	}
+
+	for _, tt := range tests {
+		if _, err := EncodeContent(tt.dataCoding, tt.text); err == nil {
+			t.Errorf("%s: EncodeContent() should fail", tt.name)
+		}
+	}
+}
+
+func TestRegisterCodec(t *testing.T) {
+	old, _ := GetCodec(PICTOGRAM)
+	RegisterCodec(PICTOGRAM, octetCodec{})
+	t.Cleanup(func() { RegisterCodec(PICTOGRAM, old) })
+
+	b, err := EncodeContent(PICTOGRAM, "\xF8\x9F")
+	if err != nil || !bytes.Equal(b, []byte{0xF8, 0x9F}) {
+		t.Errorf("EncodeContent(PICTOGRAM) after RegisterCodec = %x, %v", b, err)
+	}
+}
+
+func TestProfileCodecOverride(t *testing.T) {
+	eucjp, _ := GetCodec(EXTENDED_KANJI)
+	pf := &Profile{
+		DefaultAlphabet: LATIN1,
+		Codecs:          map[uint8]Codec{JIS: eucjp},
+	}
+
+	b, err := pf.Encode(ASCII, "é")
+	if err != nil || !bytes.Equal(b, []byte{0xE9}) {
+		t.Errorf("Encode(0) with LATIN1 default = %x, %v", b, err)
+	}
+	text, err := pf.Decode(ASCII, 0, []byte{0xE9})
+	if err != nil || text != "é" {
+		t.Errorf("Decode(0) with LATIN1 default = %q, %v", text, err)
+	}
+
+	want, _ := EncodeContent(EXTENDED_KANJI, "日本")
+	b, err = pf.Encode(JIS, "日本")
+	if err != nil || !bytes.Equal(b, want) {
+		t.Errorf("Encode(JIS) with override = %x, %v, want %x", b, err, want)
+	}
+
+	// 全局注册表不受影响
+	sjis, _ := EncodeContent(JIS, "日本")
+	if bytes.Equal(sjis, want) {
+		t.Errorf("profile override leaked into the global registry")
+	}
+}
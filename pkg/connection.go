@@ -1,6 +1,7 @@
 package pkg
 
 import (
+	"crypto/tls"
 	"encoding/binary"
 	"io"
 	"math/rand"
@@ -21,10 +22,14 @@ type Conn struct {
 	net.Conn
 	State   State
 	Version uint8
+	Profile *Profile // 对端的编码习惯
 
 	// for SequenceNum generator goroutine
 	SequenceNum <-chan uint32
 	done        chan<- struct{}
+
+	writeMu sync.Mutex   // 多个协程同时发送时保证 PDU 完整
+	stateMu sync.RWMutex // 读取协程、发送协程与 Close 可能同时访问 State
 }
 
 // The sequence number may range from: 0x00000001 to 0x7FFFFFFF.
@@ -54,21 +59,40 @@ func newSequenceNumGenerator() (<-chan uint32, chan<- struct{}) {
 	return out, done
 }
 
+// conn 可以是任意 net.Conn
 func NewConnection(conn net.Conn, v uint8) *Conn {
 	sequenceNum, done := newSequenceNumGenerator()
 	c := &Conn{
 		Conn:        conn,
 		Version:     v,
+		Profile:     DefaultProfile,
 		SequenceNum: sequenceNum,
 		done:        done,
 	}
-	tc := c.Conn.(*net.TCPConn)
-	tc.SetKeepAlive(true) //Keepalive as default
+	if kc, ok := c.Conn.(keepAliveConn); ok {
+		kc.SetKeepAlive(true) //Keepalive as default
+	}
 	return c
 }
 
+// 支持 TCP keepalive 的连接, 如 *net.TCPConn; TLS、Unix socket、net.Pipe 等连接不设置
+type keepAliveConn interface {
+	SetKeepAlive(keepalive bool) error
+}
+
+// TLS 连接的握手信息, 不是 TLS 连接时返回 false
+func (c *Conn) TLSConnectionState() (tls.ConnectionState, bool) {
+	tc, ok := c.Conn.(*tls.Conn)
+	if !ok {
+		return tls.ConnectionState{}, false
+	}
+	return tc.ConnectionState(), true
+}
+
 func (c *Conn) Close() {
 	if c != nil {
+		c.stateMu.Lock()
+		defer c.stateMu.Unlock()
 		if c.State == CONNECTION_CLOSED {
 			return
 		}
@@ -79,11 +103,19 @@ func (c *Conn) Close() {
 }
 
 func (c *Conn) SetState(state State) {
+	c.stateMu.Lock()
 	c.State = state
+	c.stateMu.Unlock()
+}
+
+func (c *Conn) closed() bool {
+	c.stateMu.RLock()
+	defer c.stateMu.RUnlock()
+	return c.State == CONNECTION_CLOSED
 }
 
 func (c *Conn) SendPkt(packet Packer, seqId uint32) error {
-	if c.State == CONNECTION_CLOSED {
+	if c.closed() {
 		return ErrConnIsClosed
 	}
 
@@ -92,7 +124,9 @@ func (c *Conn) SendPkt(packet Packer, seqId uint32) error {
 		return err
 	}
 
+	c.writeMu.Lock()
 	_, err = c.Conn.Write(data) //block write
+	c.writeMu.Unlock()
 	if err != nil {
 		return err
 	}
@@ -116,7 +150,7 @@ var readBufferPool = sync.Pool{
 }
 
 func (c *Conn) RecvAndUnpackPkt(timeout time.Duration) (Packer, error) {
-	if c.State == CONNECTION_CLOSED {
+	if c.closed() {
 		return nil, ErrConnIsClosed
 	}
 	rb := readBufferPool.Get().(*readBuffer)
@@ -193,7 +227,15 @@ func (c *Conn) RecvAndUnpackPkt(timeout time.Duration) (Packer, error) {
 	case SMPP_QUERY:
 		p = &SmppQueryReqPkt{SequenceNum: sequenceNum}
 	case SMPP_QUERY_RESP:
-		p = &SmppQueryRespPkt{SequenceNum: sequenceNum}
+		p = &SmppQueryRespPkt{SequenceNum: sequenceNum, Status: status}
+	case SMPP_CANCEL:
+		p = &SmppCancelReqPkt{SequenceNum: sequenceNum}
+	case SMPP_CANCEL_RESP:
+		p = &SmppCancelRespPkt{SequenceNum: sequenceNum, Status: status}
+	case SMPP_DATA:
+		p = &SmppDataReqPkt{SequenceNum: sequenceNum}
+	case SMPP_DATA_RESP:
+		p = &SmppDataRespPkt{SequenceNum: sequenceNum, Status: status}
 
 	default:
 		return nil, ErrCommandIDNotSupported
@@ -0,0 +1,177 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// data_sm, 内容通过 message_payload 等可选参数携带
+type SmppDataReqPkt struct {
+	ServiceType        string // 指示联系到 SMS 应用服务消息的类型
+	SourceAddrTON      uint8  // 源地址编码类型
+	SourceAddrNPI      uint8  // 源地址编码方案
+	SourceAddr         string // 提交该短消息的SME的地址
+	DestAddrTON        uint8  // 目的地址编码类型
+	DestAddrNPI        uint8  // 目的地址编码方案
+	DestinationAddr    string // 短消息的目的地址
+	EsmClass           uint8  // 指定信息模式和信息类型
+	RegisteredDelivery uint8  // 标识 SMSC 是否要状态 报告或 SME 是否要确认标识
+	DataCoding         uint8  // 短消息用户数据编码方案
+
+	// 可选参数
+	Options Options
+
+	// used in session
+	SequenceNum uint32
+}
+
+func (p *SmppDataReqPkt) Pack(seqId uint32) ([]byte, error) {
+	serviceType := NewCOctetString(p.ServiceType).Byte(6)
+	sourceAddr := NewCOctetString(p.SourceAddr).Byte(65)
+	destinationAddr := NewCOctetString(p.DestinationAddr).Byte(65)
+
+	var commandLength = uint32(int(HeaderPktLen) + 7 + len(serviceType) + len(sourceAddr) + len(destinationAddr) + p.Options.Len())
+
+	var w = newPkgWriter(commandLength)
+	// header
+	header := Header{
+		CommandLength: commandLength,
+		CommandID:     uint32(SMPP_DATA),
+		SequenceNum:   seqId,
+	}
+	w.WriteHeader(header)
+	p.SequenceNum = seqId
+
+	// body
+	w.WriteBytes(serviceType)
+	w.WriteByte(p.SourceAddrTON)
+	w.WriteByte(p.SourceAddrNPI)
+	w.WriteBytes(sourceAddr)
+	w.WriteByte(p.DestAddrTON)
+	w.WriteByte(p.DestAddrNPI)
+	w.WriteBytes(destinationAddr)
+	w.WriteByte(p.EsmClass)
+	w.WriteByte(p.RegisteredDelivery)
+	w.WriteByte(p.DataCoding)
+
+	for _, o := range p.Options {
+		b, _ := o.Byte()
+		w.WriteBytes(b)
+	}
+
+	return w.Bytes()
+}
+
+func (p *SmppDataReqPkt) Unpack(data []byte) error {
+	var r = newPkgReader(data)
+
+	p.ServiceType = string(r.ReadOCString(6))
+	p.SourceAddrTON = r.ReadUint8()
+	p.SourceAddrNPI = r.ReadUint8()
+	p.SourceAddr = string(r.ReadOCString(65))
+	p.DestAddrTON = r.ReadUint8()
+	p.DestAddrNPI = r.ReadUint8()
+	p.DestinationAddr = string(r.ReadOCString(65))
+	p.EsmClass = r.ReadUint8()
+	p.RegisteredDelivery = r.ReadUint8()
+	p.DataCoding = r.ReadUint8()
+	if err := r.Error(); err != nil {
+		return err
+	}
+
+	options, err := ParseOptions(data[len(data)-r.Len():])
+	if err != nil {
+		return err
+	}
+	p.Options = options
+
+	return nil
+}
+
+func (p *SmppDataReqPkt) String() string {
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "--- SMPP Data Req ---")
+	fmt.Fprintln(&b, "ServiceType: ", p.ServiceType)
+	fmt.Fprintln(&b, "SourceAddrTON: ", p.SourceAddrTON)
+	fmt.Fprintln(&b, "SourceAddrNPI: ", p.SourceAddrNPI)
+	fmt.Fprintln(&b, "SourceAddr: ", p.SourceAddr)
+	fmt.Fprintln(&b, "DestAddrTON: ", p.DestAddrTON)
+	fmt.Fprintln(&b, "DestAddrNPI: ", p.DestAddrNPI)
+	fmt.Fprintln(&b, "DestinationAddr: ", p.DestinationAddr)
+	fmt.Fprintln(&b, "EsmClass: ", p.EsmClass)
+	fmt.Fprintln(&b, "RegisteredDelivery: ", p.RegisteredDelivery)
+	fmt.Fprintln(&b, "DataCoding: ", p.DataCoding)
+	fmt.Fprintln(&b, "Options: ", p.Options.String())
+
+	return b.String()
+}
+
+// message_payload 可选参数的内容
+func (p *SmppDataReqPkt) MessagePayload() []byte {
+	if tlv := p.Options[TAG_MessagePayload]; tlv != nil {
+		return tlv.Value
+	}
+	return nil
+}
+
+type SmppDataRespPkt struct {
+	MsgID string
+
+	// 可选参数
+	Options Options
+
+	// used in session
+	Status      Status
+	SequenceNum uint32
+}
+
+func (p *SmppDataRespPkt) Pack(seqId uint32) ([]byte, error) {
+	msgId := NewCOctetString(p.MsgID).Byte(65)
+	var commandLength = HeaderPktLen + uint32(len(msgId)+p.Options.Len())
+
+	var w = newPkgWriter(commandLength)
+	// header
+	header := Header{
+		CommandLength: commandLength,
+		CommandID:     uint32(SMPP_DATA_RESP),
+		CommandStatus: uint32(p.Status),
+		SequenceNum:   seqId,
+	}
+	p.SequenceNum = seqId
+	w.WriteHeader(header)
+	// body
+	w.WriteBytes(msgId)
+	for _, o := range p.Options {
+		b, _ := o.Byte()
+		w.WriteBytes(b)
+	}
+	return w.Bytes()
+}
+
+func (p *SmppDataRespPkt) Unpack(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var r = newPkgReader(data)
+	p.MsgID = string(r.ReadOCString(65))
+	if err := r.Error(); err != nil {
+		return err
+	}
+
+	options, err := ParseOptions(data[len(data)-r.Len():])
+	if err != nil {
+		return err
+	}
+	p.Options = options
+	return nil
+}
+
+func (p *SmppDataRespPkt) String() string {
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "--- SMPP Data Resp ---")
+	fmt.Fprintln(&b, "MsgID: ", p.MsgID)
+	fmt.Fprintln(&b, "Status: ", p.Status)
+	fmt.Fprintln(&b, "Options: ", p.Options.String())
+	return b.String()
+}
@@ -0,0 +1,197 @@
+package pkg
+
+import "errors"
+
+var ErrDCSAlphabet = errors.New("dcs: general data coding group only supports GSM 7 bit, 8 bit data and UCS2")
+
+// 数据编码方案 (3GPP TS 23.038 第 4 节)
+// 0x00 ~ 0x0F 按 SMPP 的 data_coding 定义解释, 其余按 GSM 编码组解释
+type DCS uint8
+
+// 编码组
+type DCSGroup uint8
+
+const (
+	DCS_GROUP_SMPP           DCSGroup = iota // SMPP 定义的 data_coding
+	DCS_GROUP_GENERAL                        // 00xx 通用数据编码
+	DCS_GROUP_AUTO_DELETE                    // 01xx 自动删除
+	DCS_GROUP_RESERVED                       // 1000 ~ 1011 保留
+	DCS_GROUP_MWI_DISCARD                    // 1100 消息等待指示, 丢弃消息
+	DCS_GROUP_MWI_STORE                      // 1101 消息等待指示, 存储消息, GSM 7 bit
+	DCS_GROUP_MWI_STORE_UCS2                 // 1110 消息等待指示, 存储消息, UCS2
+	DCS_GROUP_MESSAGE_CLASS                  // 1111 数据编码/消息类别
+)
+
+// 消息类别
+const (
+	MESSAGE_CLASS_0 uint8 = iota // 立即显示 (flash)
+	MESSAGE_CLASS_1              // ME 特定
+	MESSAGE_CLASS_2              // SIM 特定
+	MESSAGE_CLASS_3              // TE 特定
+)
+
+// 消息等待指示类型
+const (
+	MWI_VOICEMAIL uint8 = iota
+	MWI_FAX
+	MWI_EMAIL
+	MWI_OTHER
+)
+
+// GSM 字符集位到 data_coding 的对应
+func gsmAlphabet(bits uint8) uint8 {
+	switch bits & 0x03 {
+	case 0:
+		return ASCII
+	case 2:
+		return UCS2
+	}
+	return BINARY
+}
+
+// data_coding 到 GSM 字符集位的对应, 只支持 GSM 7 bit、8 位数据与 UCS2
+func alphabetBits(dataCoding uint8) (uint8, bool) {
+	switch dataCoding {
+	case ASCII:
+		return 0, true
+	case OCTET, BINARY:
+		return 1, true
+	case UCS2:
+		return 2, true
+	}
+	return 0, false
+}
+
+// 通用数据编码组, class 取 MESSAGE_CLASS_*, 小于 0 时不指定消息类别
+// dataCoding 只能为 ASCII (GSM 7 bit)、OCTET/BINARY 或 UCS2, 其他字符集无法用编码组表示, 返回 ErrDCSAlphabet
+func NewDCS(dataCoding uint8, class int, compressed bool) (DCS, error) {
+	bits, ok := alphabetBits(dataCoding)
+	if !ok {
+		return 0, ErrDCSAlphabet
+	}
+	d := bits << 2
+	if class >= 0 {
+		d |= 0x10 | uint8(class)&0x03
+	}
+	if compressed {
+		d |= 0x20
+	}
+	return DCS(d), nil
+}
+
+// 立即显示的短信 (class 0)
+func FlashDCS(dataCoding uint8) (DCS, error) {
+	return NewDCS(dataCoding, int(MESSAGE_CLASS_0), false)
+}
+
+// 存储到 SIM 卡的短信 (class 2)
+func SIMDCS(dataCoding uint8) (DCS, error) {
+	return NewDCS(dataCoding, int(MESSAGE_CLASS_2), false)
+}
+
+// 消息等待指示编码组, 只有 dataCoding 为 UCS2 且 store 时使用 UCS2
+func NewMWIDCS(dataCoding uint8, store, active bool, kind uint8) DCS {
+	d := uint8(0xC0)
+	if store {
+		d = 0xD0
+		if dataCoding == UCS2 {
+			d = 0xE0
+		}
+	}
+	if active {
+		d |= 0x08
+	}
+	return DCS(d | kind&0x03)
+}
+
+func (d DCS) Group() DCSGroup {
+	switch {
+	case d < 0x10:
+		return DCS_GROUP_SMPP
+	case d < 0x40:
+		return DCS_GROUP_GENERAL
+	case d < 0x80:
+		return DCS_GROUP_AUTO_DELETE
+	case d < 0xC0:
+		return DCS_GROUP_RESERVED
+	case d < 0xD0:
+		return DCS_GROUP_MWI_DISCARD
+	case d < 0xE0:
+		return DCS_GROUP_MWI_STORE
+	case d < 0xF0:
+		return DCS_GROUP_MWI_STORE_UCS2
+	}
+	return DCS_GROUP_MESSAGE_CLASS
+}
+
+// 用于编解码的 data_coding, 压缩的内容与保留的编码组按二进制处理
+func (d DCS) DataCoding() uint8 {
+	switch d.Group() {
+	case DCS_GROUP_SMPP:
+		return uint8(d)
+	case DCS_GROUP_GENERAL, DCS_GROUP_AUTO_DELETE:
+		if d.Compressed() {
+			return BINARY
+		}
+		return gsmAlphabet(uint8(d) >> 2)
+	case DCS_GROUP_MWI_DISCARD, DCS_GROUP_MWI_STORE:
+		return ASCII
+	case DCS_GROUP_MWI_STORE_UCS2:
+		return UCS2
+	case DCS_GROUP_MESSAGE_CLASS:
+		if d&0x04 != 0 {
+			return BINARY
+		}
+		return ASCII
+	}
+	return BINARY
+}
+
+// 是否为 8 位二进制数据, 不受 SMSC 对 data_coding 0 的解释影响
+func (d DCS) Binary() bool {
+	dc := d.DataCoding()
+	return dc == OCTET || dc == BINARY
+}
+
+// 消息类别, 未指定时 ok 为 false
+func (d DCS) MessageClass() (class uint8, ok bool) {
+	switch d.Group() {
+	case DCS_GROUP_GENERAL, DCS_GROUP_AUTO_DELETE:
+		if d&0x10 == 0 {
+			return 0, false
+		}
+	case DCS_GROUP_MESSAGE_CLASS:
+	default:
+		return 0, false
+	}
+	return uint8(d) & 0x03, true
+}
+
+func (d DCS) Flash() bool {
+	class, ok := d.MessageClass()
+	return ok && class == MESSAGE_CLASS_0
+}
+
+// 内容经过压缩 (3GPP TS 23.042)
+func (d DCS) Compressed() bool {
+	g := d.Group()
+	return (g == DCS_GROUP_GENERAL || g == DCS_GROUP_AUTO_DELETE) && d&0x20 != 0
+}
+
+// 接收方读取后自动删除
+func (d DCS) AutoDelete() bool {
+	return d.Group() == DCS_GROUP_AUTO_DELETE
+}
+
+// 消息等待指示, 不属于消息等待指示编码组时 ok 为 false
+// store 为 false 时接收方可以丢弃消息内容
+func (d DCS) MWI() (kind uint8, active, store, ok bool) {
+	switch d.Group() {
+	case DCS_GROUP_MWI_DISCARD:
+	case DCS_GROUP_MWI_STORE, DCS_GROUP_MWI_STORE_UCS2:
+		store = true
+	default:
+		return 0, false, false, false
+	}
+	return uint8(d) & 0x03, d&0x08 != 0, store, true
+}
@@ -0,0 +1,105 @@
+package pkg
+
+import "testing"
+
+func TestDCS(t *testing.T) {
+	tests := []struct {
+		dcs        DCS
+		group      DCSGroup
+		dataCoding uint8
+		binary     bool
+		class      int // 小于 0 表示未指定
+		compressed bool
+	}{
+		{0x00, DCS_GROUP_SMPP, ASCII, false, -1, false},
+		{0x03, DCS_GROUP_SMPP, LATIN1, false, -1, false},
+		{0x02, DCS_GROUP_SMPP, OCTET, true, -1, false},
+		{0x04, DCS_GROUP_SMPP, BINARY, true, -1, false},
+		{0x08, DCS_GROUP_SMPP, UCS2, false, -1, false},
+		{0x10, DCS_GROUP_GENERAL, ASCII, false, 0, false},
+		{0x18, DCS_GROUP_GENERAL, UCS2, false, 0, false},
+		{0x16, DCS_GROUP_GENERAL, BINARY, true, 2, false},
+		{0x20, DCS_GROUP_GENERAL, BINARY, true, -1, true},
+		{0x48, DCS_GROUP_AUTO_DELETE, UCS2, false, -1, false},
+		{0x80, DCS_GROUP_RESERVED, BINARY, true, -1, false},
+		{0xC8, DCS_GROUP_MWI_DISCARD, ASCII, false, -1, false},
+		{0xD1, DCS_GROUP_MWI_STORE, ASCII, false, -1, false},
+		{0xE2, DCS_GROUP_MWI_STORE_UCS2, UCS2, false, -1, false},
+		{0xF0, DCS_GROUP_MESSAGE_CLASS, ASCII, false, 0, false},
+		{0xF6, DCS_GROUP_MESSAGE_CLASS, BINARY, true, 2, false},
+	}
+
+	for _, tt := range tests {
+		if g := tt.dcs.Group(); g != tt.group {
+			t.Errorf("DCS(%#02x).Group() = %d, want %d", uint8(tt.dcs), g, tt.group)
+		}
+		if dc := tt.dcs.DataCoding(); dc != tt.dataCoding {
+			t.Errorf("DCS(%#02x).DataCoding() = %d, want %d", uint8(tt.dcs), dc, tt.dataCoding)
+		}
+		if b := tt.dcs.Binary(); b != tt.binary {
+			t.Errorf("DCS(%#02x).Binary() = %v, want %v", uint8(tt.dcs), b, tt.binary)
+		}
+		class, ok := tt.dcs.MessageClass()
+		if ok != (tt.class >= 0) || (ok && int(class) != tt.class) {
+			t.Errorf("DCS(%#02x).MessageClass() = %d, %v, want %d", uint8(tt.dcs), class, ok, tt.class)
+		}
+		if c := tt.dcs.Compressed(); c != tt.compressed {
+			t.Errorf("DCS(%#02x).Compressed() = %v, want %v", uint8(tt.dcs), c, tt.compressed)
+		}
+	}
+}
+
+func TestNewDCS(t *testing.T) {
+	tests := []struct {
+		name    string
+		new     func() (DCS, error)
+		want    DCS
+		wantErr error
+	}{
+		{"flash gsm7", func() (DCS, error) { return FlashDCS(ASCII) }, 0x10, nil},
+		{"flash ucs2", func() (DCS, error) { return FlashDCS(UCS2) }, 0x18, nil},
+		{"sim binary", func() (DCS, error) { return SIMDCS(BINARY) }, 0x16, nil},
+		{"octet", func() (DCS, error) { return NewDCS(OCTET, -1, false) }, 0x04, nil},
+		{"compressed ucs2", func() (DCS, error) { return NewDCS(UCS2, -1, true) }, 0x28, nil},
+		{"latin1", func() (DCS, error) { return NewDCS(LATIN1, 0, false) }, 0, ErrDCSAlphabet},
+		{"flash jis", func() (DCS, error) { return FlashDCS(JIS) }, 0, ErrDCSAlphabet},
+	}
+	for _, tt := range tests {
+		got, err := tt.new()
+		if got != tt.want || err != tt.wantErr {
+			t.Errorf("%s = %#02x, %v, want %#02x, %v", tt.name, uint8(got), err, uint8(tt.want), tt.wantErr)
+		}
+	}
+
+	flash, _ := FlashDCS(UCS2)
+	sim, _ := SIMDCS(UCS2)
+	if !flash.Flash() || sim.Flash() {
+		t.Errorf("Flash() mismatch")
+	}
+}
+
+func TestNewMWIDCS(t *testing.T) {
+	tests := []struct {
+		name string
+		got  DCS
+		want DCS
+	}{
+		{"mwi discard voicemail on", NewMWIDCS(ASCII, false, true, MWI_VOICEMAIL), 0xC8},
+		{"mwi store fax off", NewMWIDCS(ASCII, true, false, MWI_FAX), 0xD1},
+		{"mwi store ucs2 email on", NewMWIDCS(UCS2, true, true, MWI_EMAIL), 0xEA},
+		{"mwi discard ignores ucs2", NewMWIDCS(UCS2, false, false, MWI_OTHER), 0xC3},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %#02x, want %#02x", tt.name, uint8(tt.got), uint8(tt.want))
+		}
+	}
+
+	kind, active, store, ok := DCS(0xEA).MWI()
+	if !ok || kind != MWI_EMAIL || !active || !store {
+		t.Errorf("MWI() = %d, %v, %v, %v", kind, active, store, ok)
+	}
+	if _, _, _, ok := DCS(0x08).MWI(); ok {
+		t.Errorf("MWI() of UCS2 should not be ok")
+	}
+}
@@ -190,26 +190,26 @@ func (p *SmppDeliverReqPkt) Unpack(data []byte) error {
 
 	serviceType := r.ReadOCString(6)
 	p.ServiceType = string(serviceType)
-	p.SourceAddrTON = r.ReadByte()
-	p.SourceAddrNPI = r.ReadByte()
+	p.SourceAddrTON = r.ReadUint8()
+	p.SourceAddrNPI = r.ReadUint8()
 	sourceAddr := r.ReadOCString(21)
 	p.SourceAddr = string(sourceAddr)
-	p.DestAddrTON = r.ReadByte()
-	p.DestAddrNPI = r.ReadByte()
+	p.DestAddrTON = r.ReadUint8()
+	p.DestAddrNPI = r.ReadUint8()
 	destinationAddr := r.ReadOCString(21)
 	p.DestinationAddr = string(destinationAddr)
-	p.EsmClass = r.ReadByte()
-	p.ProtocolID = r.ReadByte()
-	p.PriorityFlag = r.ReadByte()
+	p.EsmClass = r.ReadUint8()
+	p.ProtocolID = r.ReadUint8()
+	p.PriorityFlag = r.ReadUint8()
 	scheduleDeliveryTime := r.ReadOCString(1)
 	p.ScheduleDeliveryTime = string(scheduleDeliveryTime)
 	validityPeriod := r.ReadOCString(1)
 	p.ValidityPeriod = string(validityPeriod)
-	p.RegisteredDelivery = r.ReadByte()
-	p.ReplaceIfPresentFlag = r.ReadByte()
-	p.DataCoding = r.ReadByte()
-	p.SmDefaultMsgID = r.ReadByte()
-	p.SmLength = r.ReadByte()
+	p.RegisteredDelivery = r.ReadUint8()
+	p.ReplaceIfPresentFlag = r.ReadUint8()
+	p.DataCoding = r.ReadUint8()
+	p.SmDefaultMsgID = r.ReadUint8()
+	p.SmLength = r.ReadUint8()
 	msgContent := make([]byte, p.SmLength)
 	r.ReadBytes(msgContent)
 	p.ShortMessage = string(msgContent)
@@ -250,6 +250,22 @@ func (p *SmppDeliverReqPkt) String() string {
 	return b.String()
 }
 
+// 解析短消息内容中的用户数据头, 未设置 UDHI 时用户数据头为 nil
+func (p *SmppDeliverReqPkt) UDH() (UDH, []byte, error) {
+	return splitUDH(p.EsmClass, p.ShortMessage)
+}
+
+// 设置用户数据头与用户数据, 并同步 esm_class 的 UDHI 标识
+func (p *SmppDeliverReqPkt) SetUDH(h UDH, payload []byte) {
+	if len(h) == 0 {
+		p.EsmClass &^= SM_UDH_GSM
+	} else {
+		p.EsmClass |= SM_UDH_GSM
+	}
+	p.ShortMessage = string(h.Join(payload))
+	p.SmLength = uint8(len(p.ShortMessage))
+}
+
 type SmppDeliverRespPkt struct {
 	MsgID string
 
@@ -264,6 +280,7 @@ func (p *SmppDeliverRespPkt) Pack(seqId uint32) ([]byte, error) {
 	header := Header{
 		CommandLength: SmppDeliverRespPktLen,
 		CommandID:     uint32(SMPP_DELIVER_RESP),
+		CommandStatus: uint32(p.Status),
 		SequenceNum:   seqId,
 	}
 	w.WriteHeader(header)
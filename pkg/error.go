@@ -1,6 +1,9 @@
 package pkg
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// Common errors.
@@ -17,6 +20,20 @@ var (
 	ErrReadPktBodyTimeout = errors.New("read packet body timeout")
 )
 
+// 响应的 command_status 不是 ESME_ROK
+type StatusError struct {
+	Command CommandID // 响应的 command_id, 对端无法解析请求时为 SMPP_GENERIC_NACK
+	Status  Status
+}
+
+func NewStatusError(cmd CommandID, status Status) *StatusError {
+	return &StatusError{Command: cmd, Status: status}
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: command_status 0x%08x : %s", e.Command, uint32(e.Status), e.Status)
+}
+
 type OpError struct {
 	err error
 	op  string
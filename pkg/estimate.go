@@ -0,0 +1,94 @@
+package pkg
+
+// 自动选择的编码方式及其费用
+type TextEncoding struct {
+	DataCoding uint8
+	Single     uint8  // 国家语言单次转义表, 仅 GSM 7 bit 有效
+	Locking    uint8  // 国家语言锁定转义表, 仅 GSM 7 bit 有效
+	UDH        UDH    // 需要携带的国家语言信息单元
+	Content    []byte // 编码后的内容, GSM 7 bit 为每字节一个 septet
+	Length     int    // 内容长度, 单位为 septet (转义字符占 2 个)、字节或 UCS2 码元
+	Segments   int    // 拆分条数
+	PerSegment int    // 每条短信能容纳的长度, 单位同 Length
+
+	Substitutions []Substitution // 编码前对原文的替换, 没有替换时为 nil
+}
+
+func unitSize(msgFmt uint8) int {
+	if alphabetOf(msgFmt) == alphabetUCS2 {
+		return 2
+	}
+	return 1
+}
+
+func (pf *Profile) newTextEncoding(msgFmt uint8, h UDH, content []byte) *TextEncoding {
+	sg := pf.segmenter()
+	a := pf.alphabet(msgFmt)
+	e := &TextEncoding{
+		DataCoding: msgFmt,
+		UDH:        h,
+		Content:    content,
+		Length:     len(content) / unitSize(a),
+		Segments:   sg.Count(a, h, content),
+	}
+	e.Single, e.Locking = h.NationalLanguage()
+	e.PerSegment = sg.capacity(a, h, e.Segments > 1) / unitSize(a)
+	return e
+}
+
+// 为 UTF-8 文本选择拆分条数最少的编码, 候选依次为 data_coding 0、UCS2、GSM 7 bit 国家语言转义表与 Latin-1
+// 条数相同时按上述顺序优先, 国家语言转义表与 Latin-1 只在更便宜时使用
+// 国家语言转义表只在 data_coding 0 为 GSM 7 bit 时使用
+// 设置了 Transliterator 时, 需要 UCS2 的文本替换后再选择一次, 只在条数更少时采用, 结果记录在 Substitutions 中
+func (pf *Profile) SelectEncoding(text string) (*TextEncoding, error) {
+	e, err := pf.selectEncoding(text)
+	if err != nil || pf.Transliterator == nil || alphabetOf(pf.alphabet(e.DataCoding)) != alphabetUCS2 {
+		return e, err
+	}
+
+	t, subs := pf.Transliterator.Transliterate(text)
+	if len(subs) == 0 {
+		return e, nil
+	}
+	te, err := pf.selectEncoding(t)
+	if err != nil || alphabetOf(pf.alphabet(te.DataCoding)) == alphabetUCS2 || te.Segments >= e.Segments {
+		return e, nil
+	}
+	te.Substitutions = subs
+	return te, nil
+}
+
+func (pf *Profile) selectEncoding(text string) (*TextEncoding, error) {
+	if content, err := pf.Encode(ASCII, text); err == nil {
+		return pf.newTextEncoding(ASCII, nil, content), nil
+	}
+
+	ucs2, err := pf.Encode(UCS2, text)
+	if err != nil {
+		return nil, err
+	}
+	best := pf.newTextEncoding(UCS2, nil, ucs2)
+
+	if !pf.NoLanguageShift && pf.gsm7(ASCII) {
+		if septets, h, err := EncodeGSM7Auto(text, pf.segmenter(), pf.Languages...); err == nil {
+			if e := pf.newTextEncoding(ASCII, h, septets); e.Segments < best.Segments {
+				best = e
+			}
+		}
+	}
+
+	if pf.Latin1 {
+		if latin1, err := pf.Encode(LATIN1, text); err == nil {
+			if e := pf.newTextEncoding(LATIN1, nil, latin1); e.Segments < best.Segments {
+				best = e
+			}
+		}
+	}
+
+	return best, nil
+}
+
+// 按默认的编码习惯选择编码, 可用于发送前展示短信条数
+func SelectEncoding(text string) (*TextEncoding, error) {
+	return DefaultProfile.SelectEncoding(text)
+}
@@ -0,0 +1,47 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectEncoding(t *testing.T) {
+	tests := []struct {
+		name       string
+		pf         *Profile
+		text       string
+		dataCoding uint8
+		segments   int
+		perSegment int
+		single     uint8
+		locking    uint8
+	}{
+		{"gsm7 single", &Profile{}, strings.Repeat("a", 160), ASCII, 1, 160, 0, 0},
+		{"gsm7 escape counts twice", &Profile{}, strings.Repeat("€", 80), ASCII, 1, 160, 0, 0},
+		{"gsm7 multi", &Profile{}, strings.Repeat("a", 161), ASCII, 2, 153, 0, 0},
+		{"chinese", &Profile{}, "你好", UCS2, 1, 70, 0, 0},
+		{"ucs2 multi", &Profile{}, strings.Repeat("你", 71), UCS2, 2, 67, 0, 0},
+		{"shift table only when cheaper", &Profile{}, "Günaydın", UCS2, 1, 70, 0, 0},
+		{"turkish uses shift table", &Profile{}, strings.Repeat("Günaydın", 10), ASCII, 1, 155, 0, GSM7_TURKISH},
+		{"shift tables disabled", &Profile{NoLanguageShift: true}, strings.Repeat("Günaydın", 10), UCS2, 2, 67, 0, 0},
+		{"latin1 only when cheaper", &Profile{NoLanguageShift: true, Latin1: true}, strings.Repeat("õ", 10), UCS2, 1, 70, 0, 0},
+		{"latin1", &Profile{NoLanguageShift: true, Latin1: true}, strings.Repeat("õ", 100), LATIN1, 1, 140, 0, 0},
+		{"latin1 default alphabet", &Profile{DefaultAlphabet: LATIN1}, strings.Repeat("é", 140), ASCII, 1, 140, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := tt.pf.SelectEncoding(tt.text)
+			if err != nil {
+				t.Fatalf("SelectEncoding() error = %v", err)
+			}
+			if e.DataCoding != tt.dataCoding || e.Segments != tt.segments || e.PerSegment != tt.perSegment {
+				t.Errorf("SelectEncoding() = data_coding %d, %d segments of %d, want %d, %d of %d",
+					e.DataCoding, e.Segments, e.PerSegment, tt.dataCoding, tt.segments, tt.perSegment)
+			}
+			if e.Single != tt.single || e.Locking != tt.locking {
+				t.Errorf("SelectEncoding() tables = %d/%d, want %d/%d", e.Single, e.Locking, tt.single, tt.locking)
+			}
+		})
+	}
+}
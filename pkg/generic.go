@@ -22,6 +22,7 @@ func (p *SmppGenericNackReqPkt) Pack(seqId uint32) ([]byte, error) {
 	header := Header{
 		CommandLength: SmppGenericNackReqPktLen,
 		CommandID:     uint32(SMPP_GENERIC_NACK),
+		CommandStatus: uint32(p.Status),
 		SequenceNum:   seqId,
 	}
 	w.WriteHeader(header)
@@ -0,0 +1,433 @@
+package pkg
+
+import (
+	"bytes"
+)
+
+/*
+GSM 7-bit national language locking shift and single shift tables
+Source: 3GPP TS 23.038 Annex A
+*/
+
+// 国家语言标识 National Language Identifier, 0 表示 GSM 7 bit 默认字母表
+const (
+	GSM7_DEFAULT    uint8 = 0x00
+	GSM7_TURKISH    uint8 = 0x01
+	GSM7_SPANISH    uint8 = 0x02
+	GSM7_PORTUGUESE uint8 = 0x03
+	GSM7_BENGALI    uint8 = 0x04
+	GSM7_GUJARATI   uint8 = 0x05
+	GSM7_HINDI      uint8 = 0x06
+	GSM7_KANNADA    uint8 = 0x07
+	GSM7_MALAYALAM  uint8 = 0x08
+	GSM7_ORIYA      uint8 = 0x09
+	GSM7_PUNJABI    uint8 = 0x0A
+	GSM7_TAMIL      uint8 = 0x0B
+	GSM7_TELUGU     uint8 = 0x0C
+	GSM7_URDU       uint8 = 0x0D
+)
+
+// 所有国家语言
+var GSM7Languages = []uint8{
+	GSM7_TURKISH, GSM7_SPANISH, GSM7_PORTUGUESE, GSM7_BENGALI, GSM7_GUJARATI, GSM7_HINDI, GSM7_KANNADA,
+	GSM7_MALAYALAM, GSM7_ORIYA, GSM7_PUNJABI, GSM7_TAMIL, GSM7_TELUGU, GSM7_URDU,
+}
+
+type gsm7Table struct {
+	forward map[rune]byte
+	reverse map[byte]rune
+}
+
+func newGSM7Table(reverse map[byte]rune) *gsm7Table {
+	t := &gsm7Table{
+		forward: make(map[rune]byte, len(reverse)),
+		reverse: reverse,
+	}
+	for b := 0; b < 0x80; b++ {
+		if r, ok := reverse[byte(b)]; ok {
+			if _, dup := t.forward[r]; !dup {
+				t.forward[r] = byte(b)
+			}
+		}
+	}
+	return t
+}
+
+// 按 3GPP 表的顺序列出 0x00-0x7F 位置的字符, 未定义的位置为 0xFFFF
+func newCharTable(chars []rune) *gsm7Table {
+	reverse := make(map[byte]rune, len(chars))
+	for b, r := range chars {
+		if r != 0xFFFF && b != escapeSequence {
+			reverse[byte(b)] = r
+		}
+	}
+	return newGSM7Table(reverse)
+}
+
+func replaceTable(base map[byte]rune, diff map[byte]rune) map[byte]rune {
+	m := make(map[byte]rune, len(base)+len(diff))
+	for b, r := range base {
+		m[b] = r
+	}
+	for b, r := range diff {
+		m[b] = r
+	}
+	return m
+}
+
+var (
+	defaultLockingTable = &gsm7Table{forward: forwardLookup, reverse: reverseLookup}
+	defaultShiftTable   = &gsm7Table{forward: forwardEscape, reverse: reverseEscape}
+)
+
+// 锁定转义表, 替换默认字母表
+var lockingTables = map[uint8]*gsm7Table{
+	GSM7_TURKISH: newGSM7Table(replaceTable(reverseLookup, map[byte]rune{
+		0x04: '€', 0x07: 'ı', 0x0B: 'Ğ', 0x0C: 'ğ', 0x1C: 'Ş', 0x1D: 'ş', 0x40: 'İ', 0x60: 'ç',
+	})),
+	GSM7_PORTUGUESE: newGSM7Table(replaceTable(reverseLookup, map[byte]rune{
+		0x04: 'ê', 0x06: 'ú', 0x07: 'í', 0x08: 'ó', 0x09: 'ç', 0x0B: 'Ô', 0x0C: 'ô', 0x0E: 'Á',
+		0x0F: 'á', 0x12: 'ª', 0x13: 'Ç', 0x14: 'À', 0x15: '∞', 0x16: '^', 0x17: '\\', 0x18: '€',
+		0x19: 'Ó', 0x1A: '|', 0x1C: 'Â', 0x1D: 'â', 0x1E: 'Ê', 0x24: 'º', 0x40: 'Í', 0x5B: 'Ã',
+		0x5C: 'Õ', 0x5D: 'Ú', 0x60: '~', 0x7B: 'ã', 0x7C: 'õ', 0x7D: '`',
+	})),
+	GSM7_BENGALI: newCharTable([]rune{
+		0x0981, 0x0982, 0x0983, 0x0985, 0x0986, 0x0987, 0x0988, 0x0989, 0x098A, 0x098B, '\n', 0x098C, 0xFFFF, '\r', 0xFFFF, 0x098F,
+		0x0990, 0xFFFF, 0xFFFF, 0x0993, 0x0994, 0x0995, 0x0996, 0x0997, 0x0998, 0x0999, 0x099A, 0xFFFF, 0x099B, 0x099C, 0x099D, 0x099E,
+		' ', '!', 0x099F, 0x09A0, 0x09A1, 0x09A2, 0x09A3, 0x09A4, ')', '(', 0x09A5, 0x09A6, ',', 0x09A7, '.', 0x09A8,
+		'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', ';', 0xFFFF, 0x09AA, 0x09AB, '?',
+		0x09AC, 0x09AD, 0x09AE, 0x09AF, 0x09B0, 0xFFFF, 0x09B2, 0xFFFF, 0xFFFF, 0xFFFF, 0x09B6, 0x09B7, 0x09B8, 0x09B9, 0x09BC, 0x09BD,
+		0x09BE, 0x09BF, 0x09C0, 0x09C1, 0x09C2, 0x09C3, 0x09C4, 0xFFFF, 0xFFFF, 0x09C7, 0x09C8, 0xFFFF, 0xFFFF, 0x09CB, 0x09CC, 0x09CD,
+		0x09CE, 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o',
+		'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', 0x09D7, 0x09DC, 0x09DD, 0x09F0, 0x09F1,
+	}),
+	GSM7_GUJARATI: newCharTable([]rune{
+		0x0A81, 0x0A82, 0x0A83, 0x0A85, 0x0A86, 0x0A87, 0x0A88, 0x0A89, 0x0A8A, 0x0A8B, '\n', 0x0A8C, 0x0A8D, '\r', 0xFFFF, 0x0A8F,
+		0x0A90, 0x0A91, 0xFFFF, 0x0A93, 0x0A94, 0x0A95, 0x0A96, 0x0A97, 0x0A98, 0x0A99, 0x0A9A, 0xFFFF, 0x0A9B, 0x0A9C, 0x0A9D, 0x0A9E,
+		' ', '!', 0x0A9F, 0x0AA0, 0x0AA1, 0x0AA2, 0x0AA3, 0x0AA4, ')', '(', 0x0AA5, 0x0AA6, ',', 0x0AA7, '.', 0x0AA8,
+		'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', ';', 0xFFFF, 0x0AAA, 0x0AAB, '?',
+		0x0AAC, 0x0AAD, 0x0AAE, 0x0AAF, 0x0AB0, 0xFFFF, 0x0AB2, 0x0AB3, 0xFFFF, 0x0AB5, 0x0AB6, 0x0AB7, 0x0AB8, 0x0AB9, 0x0ABC, 0x0ABD,
+		0x0ABE, 0x0ABF, 0x0AC0, 0x0AC1, 0x0AC2, 0x0AC3, 0x0AC4, 0x0AC5, 0xFFFF, 0x0AC7, 0x0AC8, 0x0AC9, 0xFFFF, 0x0ACB, 0x0ACC, 0x0ACD,
+		0x0AD0, 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o',
+		'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', 0x0AE0, 0x0AE1, 0x0AE2, 0x0AE3, 0x0AF1,
+	}),
+	GSM7_HINDI: newCharTable([]rune{
+		0x0901, 0x0902, 0x0903, 0x0905, 0x0906, 0x0907, 0x0908, 0x0909, 0x090A, 0x090B, '\n', 0x090C, 0x090D, '\r', 0x090E, 0x090F,
+		0x0910, 0x0911, 0x0912, 0x0913, 0x0914, 0x0915, 0x0916, 0x0917, 0x0918, 0x0919, 0x091A, 0xFFFF, 0x091B, 0x091C, 0x091D, 0x091E,
+		' ', '!', 0x091F, 0x0920, 0x0921, 0x0922, 0x0923, 0x0924, ')', '(', 0x0925, 0x0926, ',', 0x0927, '.', 0x0928,
+		'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', ';', 0x0929, 0x092A, 0x092B, '?',
+		0x092C, 0x092D, 0x092E, 0x092F, 0x0930, 0x0931, 0x0932, 0x0933, 0x0934, 0x0935, 0x0936, 0x0937, 0x0938, 0x0939, 0x093C, 0x093D,
+		0x093E, 0x093F, 0x0940, 0x0941, 0x0942, 0x0943, 0x0944, 0x0945, 0x0946, 0x0947, 0x0948, 0x0949, 0x094A, 0x094B, 0x094C, 0x094D,
+		0x0950, 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o',
+		'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', 0x0972, 0x097B, 0x097C, 0x097E, 0x097F,
+	}),
+	GSM7_KANNADA: newCharTable([]rune{
+		0xFFFF, 0x0C82, 0x0C83, 0x0C85, 0x0C86, 0x0C87, 0x0C88, 0x0C89, 0x0C8A, 0x0C8B, '\n', 0x0C8C, 0xFFFF, '\r', 0x0C8E, 0x0C8F,
+		0x0C90, 0xFFFF, 0x0C92, 0x0C93, 0x0C94, 0x0C95, 0x0C96, 0x0C97, 0x0C98, 0x0C99, 0x0C9A, 0xFFFF, 0x0C9B, 0x0C9C, 0x0C9D, 0x0C9E,
+		' ', '!', 0x0C9F, 0x0CA0, 0x0CA1, 0x0CA2, 0x0CA3, 0x0CA4, ')', '(', 0x0CA5, 0x0CA6, ',', 0x0CA7, '.', 0x0CA8,
+		'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', ';', 0xFFFF, 0x0CAA, 0x0CAB, '?',
+		0x0CAC, 0x0CAD, 0x0CAE, 0x0CAF, 0x0CB0, 0x0CB1, 0x0CB2, 0x0CB3, 0xFFFF, 0x0CB5, 0x0CB6, 0x0CB7, 0x0CB8, 0x0CB9, 0x0CBC, 0x0CBD,
+		0x0CBE, 0x0CBF, 0x0CC0, 0x0CC1, 0x0CC2, 0x0CC3, 0x0CC4, 0xFFFF, 0x0CC6, 0x0CC7, 0x0CC8, 0xFFFF, 0x0CCA, 0x0CCB, 0x0CCC, 0x0CCD,
+		0x0CD5, 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o',
+		'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', 0x0CD6, 0x0CE0, 0x0CE1, 0x0CE2, 0x0CE3,
+	}),
+	GSM7_MALAYALAM: newCharTable([]rune{
+		0xFFFF, 0x0D02, 0x0D03, 0x0D05, 0x0D06, 0x0D07, 0x0D08, 0x0D09, 0x0D0A, 0x0D0B, '\n', 0x0D0C, 0xFFFF, '\r', 0x0D0E, 0x0D0F,
+		0x0D10, 0xFFFF, 0x0D12, 0x0D13, 0x0D14, 0x0D15, 0x0D16, 0x0D17, 0x0D18, 0x0D19, 0x0D1A, 0xFFFF, 0x0D1B, 0x0D1C, 0x0D1D, 0x0D1E,
+		' ', '!', 0x0D1F, 0x0D20, 0x0D21, 0x0D22, 0x0D23, 0x0D24, ')', '(', 0x0D25, 0x0D26, ',', 0x0D27, '.', 0x0D28,
+		'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', ';', 0xFFFF, 0x0D2A, 0x0D2B, '?',
+		0x0D2C, 0x0D2D, 0x0D2E, 0x0D2F, 0x0D30, 0x0D31, 0x0D32, 0x0D33, 0x0D34, 0x0D35, 0x0D36, 0x0D37, 0x0D38, 0x0D39, 0xFFFF, 0x0D3D,
+		0x0D3E, 0x0D3F, 0x0D40, 0x0D41, 0x0D42, 0x0D43, 0x0D44, 0xFFFF, 0x0D46, 0x0D47, 0x0D48, 0xFFFF, 0x0D4A, 0x0D4B, 0x0D4C, 0x0D4D,
+		0x0D57, 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o',
+		'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', 0x0D60, 0x0D61, 0x0D62, 0x0D63, 0x0D79,
+	}),
+	GSM7_ORIYA: newCharTable([]rune{
+		0x0B01, 0x0B02, 0x0B03, 0x0B05, 0x0B06, 0x0B07, 0x0B08, 0x0B09, 0x0B0A, 0x0B0B, '\n', 0x0B0C, 0xFFFF, '\r', 0xFFFF, 0x0B0F,
+		0x0B10, 0xFFFF, 0xFFFF, 0x0B13, 0x0B14, 0x0B15, 0x0B16, 0x0B17, 0x0B18, 0x0B19, 0x0B1A, 0xFFFF, 0x0B1B, 0x0B1C, 0x0B1D, 0x0B1E,
+		' ', '!', 0x0B1F, 0x0B20, 0x0B21, 0x0B22, 0x0B23, 0x0B24, ')', '(', 0x0B25, 0x0B26, ',', 0x0B27, '.', 0x0B28,
+		'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', ';', 0xFFFF, 0x0B2A, 0x0B2B, '?',
+		0x0B2C, 0x0B2D, 0x0B2E, 0x0B2F, 0x0B30, 0xFFFF, 0x0B32, 0x0B33, 0xFFFF, 0x0B35, 0x0B36, 0x0B37, 0x0B38, 0x0B39, 0x0B3C, 0x0B3D,
+		0x0B3E, 0x0B3F, 0x0B40, 0x0B41, 0x0B42, 0x0B43, 0x0B44, 0xFFFF, 0xFFFF, 0x0B47, 0x0B48, 0xFFFF, 0xFFFF, 0x0B4B, 0x0B4C, 0x0B4D,
+		0x0B56, 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o',
+		'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', 0x0B57, 0x0B60, 0x0B61, 0x0B62, 0x0B63,
+	}),
+	GSM7_PUNJABI: newCharTable([]rune{
+		0x0A01, 0x0A02, 0x0A03, 0x0A05, 0x0A06, 0x0A07, 0x0A08, 0x0A09, 0x0A0A, 0xFFFF, '\n', 0xFFFF, 0xFFFF, '\r', 0xFFFF, 0x0A0F,
+		0x0A10, 0xFFFF, 0xFFFF, 0x0A13, 0x0A14, 0x0A15, 0x0A16, 0x0A17, 0x0A18, 0x0A19, 0x0A1A, 0xFFFF, 0x0A1B, 0x0A1C, 0x0A1D, 0x0A1E,
+		' ', '!', 0x0A1F, 0x0A20, 0x0A21, 0x0A22, 0x0A23, 0x0A24, ')', '(', 0x0A25, 0x0A26, ',', 0x0A27, '.', 0x0A28,
+		'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', ';', 0xFFFF, 0x0A2A, 0x0A2B, '?',
+		0x0A2C, 0x0A2D, 0x0A2E, 0x0A2F, 0x0A30, 0xFFFF, 0x0A32, 0x0A33, 0xFFFF, 0x0A35, 0x0A36, 0xFFFF, 0x0A38, 0x0A39, 0x0A3C, 0xFFFF,
+		0x0A3E, 0x0A3F, 0x0A40, 0x0A41, 0x0A42, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0x0A47, 0x0A48, 0xFFFF, 0xFFFF, 0x0A4B, 0x0A4C, 0x0A4D,
+		0x0A51, 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o',
+		'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', 0x0A70, 0x0A71, 0x0A72, 0x0A73, 0x0A74,
+	}),
+	GSM7_TAMIL: newCharTable([]rune{
+		0xFFFF, 0x0B82, 0x0B83, 0x0B85, 0x0B86, 0x0B87, 0x0B88, 0x0B89, 0x0B8A, 0xFFFF, '\n', 0xFFFF, 0xFFFF, '\r', 0x0B8E, 0x0B8F,
+		0x0B90, 0xFFFF, 0x0B92, 0x0B93, 0x0B94, 0x0B95, 0xFFFF, 0xFFFF, 0xFFFF, 0x0B99, 0x0B9A, 0xFFFF, 0xFFFF, 0x0B9C, 0xFFFF, 0x0B9E,
+		' ', '!', 0x0B9F, 0xFFFF, 0xFFFF, 0xFFFF, 0x0BA3, 0x0BA4, ')', '(', 0xFFFF, 0xFFFF, ',', 0xFFFF, '.', 0x0BA8,
+		'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', ';', 0x0BA9, 0x0BAA, 0xFFFF, '?',
+		0xFFFF, 0xFFFF, 0x0BAE, 0x0BAF, 0x0BB0, 0x0BB1, 0x0BB2, 0x0BB3, 0x0BB4, 0x0BB5, 0x0BB6, 0x0BB7, 0x0BB8, 0x0BB9, 0xFFFF, 0xFFFF,
+		0x0BBE, 0x0BBF, 0x0BC0, 0x0BC1, 0x0BC2, 0xFFFF, 0xFFFF, 0xFFFF, 0x0BC6, 0x0BC7, 0x0BC8, 0xFFFF, 0x0BCA, 0x0BCB, 0x0BCC, 0x0BCD,
+		0x0BD0, 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o',
+		'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', 0x0BD7, 0x0BF0, 0x0BF1, 0x0BF2, 0x0BF9,
+	}),
+	GSM7_TELUGU: newCharTable([]rune{
+		0x0C01, 0x0C02, 0x0C03, 0x0C05, 0x0C06, 0x0C07, 0x0C08, 0x0C09, 0x0C0A, 0x0C0B, '\n', 0x0C0C, 0xFFFF, '\r', 0x0C0E, 0x0C0F,
+		0x0C10, 0xFFFF, 0x0C12, 0x0C13, 0x0C14, 0x0C15, 0x0C16, 0x0C17, 0x0C18, 0x0C19, 0x0C1A, 0xFFFF, 0x0C1B, 0x0C1C, 0x0C1D, 0x0C1E,
+		' ', '!', 0x0C1F, 0x0C20, 0x0C21, 0x0C22, 0x0C23, 0x0C24, ')', '(', 0x0C25, 0x0C26, ',', 0x0C27, '.', 0x0C28,
+		'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', ';', 0xFFFF, 0x0C2A, 0x0C2B, '?',
+		0x0C2C, 0x0C2D, 0x0C2E, 0x0C2F, 0x0C30, 0x0C31, 0x0C32, 0x0C33, 0xFFFF, 0x0C35, 0x0C36, 0x0C37, 0x0C38, 0x0C39, 0xFFFF, 0x0C3D,
+		0x0C3E, 0x0C3F, 0x0C40, 0x0C41, 0x0C42, 0x0C43, 0x0C44, 0xFFFF, 0x0C46, 0x0C47, 0x0C48, 0xFFFF, 0x0C4A, 0x0C4B, 0x0C4C, 0x0C4D,
+		0x0C55, 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o',
+		'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', 0x0C56, 0x0C60, 0x0C61, 0x0C62, 0x0C63,
+	}),
+	GSM7_URDU: newCharTable([]rune{
+		0x0627, 0x0622, 0x0628, 0x067B, 0x0680, 0x067E, 0x06A6, 0x062A, 0x06C2, 0x067F, '\n', 0x0679, 0x067D, '\r', 0x067A, 0x067C,
+		0x062B, 0x062C, 0x0681, 0x0684, 0x0683, 0x0685, 0x0686, 0x0687, 0x062D, 0x062E, 0x062F, 0xFFFF, 0x068C, 0x0688, 0x0689, 0x068A,
+		' ', '!', 0x068F, 0x068D, 0x0630, 0x0631, 0x0691, 0x0693, ')', '(', 0x0699, 0x0632, ',', 0x0696, '.', 0x0698,
+		'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', ';', 0x069A, 0x0633, 0x0634, '?',
+		0x0635, 0x0636, 0x0637, 0x0638, 0x0639, 0x0641, 0x0642, 0x06A9, 0x06AA, 0x06AB, 0x06AF, 0x06B3, 0x06B1, 0x0644, 0x0645, 0x0646,
+		0x06BA, 0x06BB, 0x06BC, 0x0648, 0x06C4, 0x06D5, 0x06C1, 0x06BE, 0x0621, 0x06CC, 0x06D0, 0x06D2, 0x064D, 0x0650, 0x064F, 0x0657,
+		0x0654, 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o',
+		'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', 0x0655, 0x0651, 0x0653, 0x0656, 0x0670,
+	}),
+}
+
+// 单次转义表, 替换默认扩展表
+var shiftTables = map[uint8]*gsm7Table{
+	GSM7_TURKISH: newGSM7Table(replaceTable(reverseEscape, map[byte]rune{
+		0x47: 'Ğ', 0x49: 'İ', 0x53: 'Ş', 0x63: 'ç', 0x67: 'ğ', 0x69: 'ı', 0x73: 'ş',
+	})),
+	GSM7_SPANISH: newGSM7Table(replaceTable(reverseEscape, map[byte]rune{
+		0x09: 'ç', 0x41: 'Á', 0x49: 'Í', 0x4F: 'Ó', 0x55: 'Ú', 0x61: 'á', 0x69: 'í', 0x6F: 'ó', 0x75: 'ú',
+	})),
+	GSM7_PORTUGUESE: newGSM7Table(replaceTable(reverseEscape, map[byte]rune{
+		0x05: 'ê', 0x09: 'ç', 0x0B: 'Ô', 0x0C: 'ô', 0x0E: 'Á', 0x0F: 'á', 0x12: 'Φ', 0x13: 'Γ',
+		0x15: 'Ω', 0x16: 'Π', 0x17: 'Ψ', 0x18: 'Σ', 0x19: 'Θ', 0x1F: 'Ê', 0x41: 'À', 0x49: 'Í',
+		0x4F: 'Ó', 0x55: 'Ú', 0x5B: 'Ã', 0x5C: 'Õ', 0x61: 'Â', 0x69: 'í', 0x6F: 'ó', 0x75: 'ú',
+		0x7B: 'ã', 0x7C: 'õ', 0x7F: 'â',
+	})),
+	GSM7_BENGALI: newCharTable([]rune{
+		'@', '£', '$', '¥', '¿', '"', '¤', '%', '&', '\'', '\f', '*', '+', 0xFFFF, '-', '/',
+		'<', '=', '>', '¡', '^', '¡', '_', '#', '*', 0x0964, 0x0965, 0xFFFF, 0x09E6, 0x09E7, 0x09E8, 0x09E9,
+		0x09EA, 0x09EB, 0x09EC, 0x09ED, 0x09EE, 0x09EF, 0x09DF, 0x09E0, '{', '}', 0x09E1, 0x09E2, 0x09E3, 0x09F2, 0x09F3, '\\',
+		0x09F4, 0x09F5, 0x09F6, 0x09F7, 0x09F8, 0x09F9, 0x09FA, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '[', '~', ']', 0xFFFF,
+		'|', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O',
+		'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '€', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+	}),
+	GSM7_GUJARATI: newCharTable([]rune{
+		'@', '£', '$', '¥', '¿', '"', '¤', '%', '&', '\'', '\f', '*', '+', 0xFFFF, '-', '/',
+		'<', '=', '>', '¡', '^', '¡', '_', '#', '*', 0x0964, 0x0965, 0xFFFF, 0x0AE6, 0x0AE7, 0x0AE8, 0x0AE9,
+		0x0AEA, 0x0AEB, 0x0AEC, 0x0AED, 0x0AEE, 0x0AEF, 0xFFFF, 0xFFFF, '{', '}', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '\\',
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '[', '~', ']', 0xFFFF,
+		'|', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O',
+		'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '€', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+	}),
+	GSM7_HINDI: newCharTable([]rune{
+		'@', '£', '$', '¥', '¿', '"', '¤', '%', '&', '\'', '\f', '*', '+', 0xFFFF, '-', '/',
+		'<', '=', '>', '¡', '^', '¡', '_', '#', '*', 0x0964, 0x0965, 0xFFFF, 0x0966, 0x0967, 0x0968, 0x0969,
+		0x096A, 0x096B, 0x096C, 0x096D, 0x096E, 0x096F, 0x0951, 0x0952, '{', '}', 0x0953, 0x0954, 0x0958, 0x0959, 0x095A, '\\',
+		0x095B, 0x095C, 0x095D, 0x095E, 0x095F, 0x0960, 0x0961, 0x0962, 0x0963, 0x0970, 0x0971, 0xFFFF, '[', '~', ']', 0xFFFF,
+		'|', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O',
+		'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '€', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+	}),
+	GSM7_KANNADA: newCharTable([]rune{
+		'@', '£', '$', '¥', '¿', '"', '¤', '%', '&', '\'', '\f', '*', '+', 0xFFFF, '-', '/',
+		'<', '=', '>', '¡', '^', '¡', '_', '#', '*', 0x0964, 0x0965, 0xFFFF, 0x0CE6, 0x0CE7, 0x0CE8, 0x0CE9,
+		0x0CEA, 0x0CEB, 0x0CEC, 0x0CED, 0x0CEE, 0x0CEF, 0x0CDE, 0x0CF1, '{', '}', 0x0CF2, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '\\',
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '[', '~', ']', 0xFFFF,
+		'|', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O',
+		'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '€', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+	}),
+	GSM7_MALAYALAM: newCharTable([]rune{
+		'@', '£', '$', '¥', '¿', '"', '¤', '%', '&', '\'', '\f', '*', '+', 0xFFFF, '-', '/',
+		'<', '=', '>', '¡', '^', '¡', '_', '#', '*', 0x0964, 0x0965, 0xFFFF, 0x0D66, 0x0D67, 0x0D68, 0x0D69,
+		0x0D6A, 0x0D6B, 0x0D6C, 0x0D6D, 0x0D6E, 0x0D6F, 0x0D70, 0x0D71, '{', '}', 0x0D72, 0x0D73, 0x0D74, 0x0D75, 0x0D7A, '\\',
+		0x0D7B, 0x0D7C, 0x0D7D, 0x0D7E, 0x0D7F, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '[', '~', ']', 0xFFFF,
+		'|', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O',
+		'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '€', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+	}),
+	GSM7_ORIYA: newCharTable([]rune{
+		'@', '£', '$', '¥', '¿', '"', '¤', '%', '&', '\'', '\f', '*', '+', 0xFFFF, '-', '/',
+		'<', '=', '>', '¡', '^', '¡', '_', '#', '*', 0x0964, 0x0965, 0xFFFF, 0x0B66, 0x0B67, 0x0B68, 0x0B69,
+		0x0B6A, 0x0B6B, 0x0B6C, 0x0B6D, 0x0B6E, 0x0B6F, 0x0B5C, 0x0B5D, '{', '}', 0x0B5F, 0x0B70, 0x0B71, 0xFFFF, 0xFFFF, '\\',
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '[', '~', ']', 0xFFFF,
+		'|', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O',
+		'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '€', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+	}),
+	GSM7_PUNJABI: newCharTable([]rune{
+		'@', '£', '$', '¥', '¿', '"', '¤', '%', '&', '\'', '\f', '*', '+', 0xFFFF, '-', '/',
+		'<', '=', '>', '¡', '^', '¡', '_', '#', '*', 0x0964, 0x0965, 0xFFFF, 0x0A66, 0x0A67, 0x0A68, 0x0A69,
+		0x0A6A, 0x0A6B, 0x0A6C, 0x0A6D, 0x0A6E, 0x0A6F, 0x0A59, 0x0A5A, '{', '}', 0x0A5B, 0x0A5C, 0x0A5E, 0x0A75, 0xFFFF, '\\',
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '[', '~', ']', 0xFFFF,
+		'|', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O',
+		'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '€', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+	}),
+	GSM7_TAMIL: newCharTable([]rune{
+		'@', '£', '$', '¥', '¿', '"', '¤', '%', '&', '\'', '\f', '*', '+', 0xFFFF, '-', '/',
+		'<', '=', '>', '¡', '^', '¡', '_', '#', '*', 0x0964, 0x0965, 0xFFFF, 0x0BE6, 0x0BE7, 0x0BE8, 0x0BE9,
+		0x0BEA, 0x0BEB, 0x0BEC, 0x0BED, 0x0BEE, 0x0BEF, 0x0BF3, 0x0BF4, '{', '}', 0x0BF5, 0x0BF6, 0x0BF7, 0x0BF8, 0x0BFA, '\\',
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '[', '~', ']', 0xFFFF,
+		'|', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O',
+		'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '€', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+	}),
+	GSM7_TELUGU: newCharTable([]rune{
+		'@', '£', '$', '¥', '¿', '"', '¤', '%', '&', '\'', '\f', '*', '+', 0xFFFF, '-', '/',
+		'<', '=', '>', '¡', '^', '¡', '_', '#', '*', 0x0964, 0x0965, 0xFFFF, 0x0C66, 0x0C67, 0x0C68, 0x0C69,
+		0x0C6A, 0x0C6B, 0x0C6C, 0x0C6D, 0x0C6E, 0x0C6F, 0x0C58, 0x0C59, '{', '}', 0x0C78, 0x0C79, 0x0C7A, 0x0C7B, 0x0C7C, '\\',
+		0x0C7D, 0x0C7E, 0x0C7F, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '[', '~', ']', 0xFFFF,
+		'|', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O',
+		'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '€', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+	}),
+	GSM7_URDU: newCharTable([]rune{
+		'@', '£', '$', '¥', '¿', '"', '¤', '%', '&', '\'', '\f', '*', '+', 0xFFFF, '-', '/',
+		'<', '=', '>', '¡', '^', '¡', '_', '#', '*', 0x0600, 0x0601, 0xFFFF, 0x06F0, 0x06F1, 0x06F2, 0x06F3,
+		0x06F4, 0x06F5, 0x06F6, 0x06F7, 0x06F8, 0x06F9, 0x060C, 0x060D, '{', '}', 0x060E, 0x060F, 0x0610, 0x0611, 0x0612, '\\',
+		0x0613, 0x0614, 0x061B, 0x061F, 0x0640, 0x0652, 0x0658, 0x066B, 0x066C, 0x0672, 0x0673, 0x06CD, '[', '~', ']', 0x06D4,
+		'|', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O',
+		'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, '€', 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+		0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF,
+	}),
+}
+
+func gsm7Tables(single, locking uint8) (*gsm7Table, *gsm7Table, bool) {
+	l, s := defaultLockingTable, defaultShiftTable
+	if locking != GSM7_DEFAULT {
+		if l = lockingTables[locking]; l == nil {
+			return nil, nil, false
+		}
+	}
+	if single != GSM7_DEFAULT {
+		if s = shiftTables[single]; s == nil {
+			return nil, nil, false
+		}
+	}
+	return l, s, true
+}
+
+// 使用指定的单次转义表与锁定转义表编码, 每个 septet 占一个字节
+func EncodeGSM7Lang(s string, single, locking uint8) ([]byte, error) {
+	l, sh, ok := gsm7Tables(single, locking)
+	if !ok {
+		return nil, ErrInvalidCharacter
+	}
+
+	septets := make([]byte, 0, len(s))
+	for _, r := range s {
+		if v, ok := l.forward[r]; ok {
+			septets = append(septets, v)
+		} else if v, ok := sh.forward[r]; ok {
+			septets = append(septets, escapeSequence, v)
+		} else {
+			return nil, ErrInvalidCharacter
+		}
+	}
+	return septets, nil
+}
+
+// 使用指定的单次转义表与锁定转义表解码
+func DecodeGSM7Lang(b []byte, single, locking uint8) (string, error) {
+	l, sh, ok := gsm7Tables(single, locking)
+	if !ok {
+		return "", ErrInvalidByte
+	}
+
+	builder := bytes.NewBufferString("")
+	for i := 0; i < len(b); i++ {
+		if b[i] == escapeSequence {
+			i++
+			if i >= len(b) {
+				return "", ErrInvalidByte
+			}
+			r, ok := sh.reverse[b[i]]
+			if !ok {
+				return "", ErrInvalidByte
+			}
+			builder.WriteRune(r)
+		} else if r, ok := l.reverse[b[i]]; ok {
+			builder.WriteRune(r)
+		} else {
+			return "", ErrInvalidByte
+		}
+	}
+	return builder.String(), nil
+}
+
+// 国家语言转义表对应的信息单元, 都为默认字母表时为 nil
+func gsm7LangUDH(single, locking uint8) UDH {
+	var h UDH
+	if single != GSM7_DEFAULT {
+		h = append(h, NewSingleShiftIE(single))
+	}
+	if locking != GSM7_DEFAULT {
+		h = append(h, NewLockingShiftIE(locking))
+	}
+	return h
+}
+
+// 在 langs (为空时为所有国家语言) 中选择拆分条数最少的转义表组合编码
+// 返回编码后的内容以及需要携带的国家语言信息单元
+func EncodeGSM7Auto(s string, sg *Segmenter, langs ...uint8) ([]byte, UDH, error) {
+	if sg == nil {
+		sg = DefaultSegmenter
+	}
+	if len(langs) == 0 {
+		langs = GSM7Languages
+	}
+
+	var (
+		best      []byte
+		bestUDH   UDH
+		bestCount int
+	)
+	candidates := append([]uint8{GSM7_DEFAULT}, langs...)
+	for _, locking := range candidates {
+		if locking != GSM7_DEFAULT && lockingTables[locking] == nil {
+			continue
+		}
+		for _, single := range candidates {
+			if single != GSM7_DEFAULT && shiftTables[single] == nil {
+				continue
+			}
+			septets, err := EncodeGSM7Lang(s, single, locking)
+			if err != nil {
+				continue
+			}
+			h := gsm7LangUDH(single, locking)
+			count := sg.Count(ASCII, h, septets)
+			if best == nil || count < bestCount ||
+				(count == bestCount && len(septets)+h.Len() < len(best)+bestUDH.Len()) {
+				best, bestUDH, bestCount = septets, h, count
+			}
+		}
+	}
+
+	if best == nil {
+		return nil, nil, ErrInvalidCharacter
+	}
+	return best, bestUDH, nil
+}
+
+// 按用户数据头中的国家语言信息单元解码 GSM 7 bit 用户数据, 其他编码同 GetUtf8Content
+func DecodeUserData(msgFmt uint8, h UDH, ud []byte) (string, error) {
+	return DefaultProfile.DecodeUserData(msgFmt, h, ud)
+}
@@ -0,0 +1,97 @@
+package pkg
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGSM7LangRoundTrip(t *testing.T) {
+	tests := []struct {
+		name            string
+		text            string
+		single, locking uint8
+		want            []byte // 为 nil 时只检查往返
+	}{
+		{"default", "Hello {}", GSM7_DEFAULT, GSM7_DEFAULT, []byte{'H', 'e', 'l', 'l', 'o', ' ', 0x1B, 0x28, 0x1B, 0x29}},
+		{"turkish single shift", "ğış", GSM7_TURKISH, GSM7_DEFAULT, []byte{0x1B, 0x67, 0x1B, 0x69, 0x1B, 0x73}},
+		{"turkish locking shift", "ğış", GSM7_DEFAULT, GSM7_TURKISH, nil},
+		{"spanish single shift", "á", GSM7_SPANISH, GSM7_DEFAULT, []byte{0x1B, 0x61}},
+		{"portuguese locking shift", "ãõ", GSM7_DEFAULT, GSM7_PORTUGUESE, nil},
+		{"hindi", "नमस्ते", GSM7_HINDI, GSM7_HINDI, nil},
+		{"urdu", "سلام", GSM7_URDU, GSM7_URDU, nil},
+		{"bengali single shift", "৳৺", GSM7_BENGALI, GSM7_DEFAULT, []byte{0x1B, 0x2E, 0x1B, 0x36}},
+		{"tamil single shift", "௳", GSM7_TAMIL, GSM7_DEFAULT, []byte{0x1B, 0x26}},
+		{"telugu single shift", "౸౿", GSM7_TELUGU, GSM7_DEFAULT, []byte{0x1B, 0x2A, 0x1B, 0x32}},
+		{"malayalam locking shift", "മ", GSM7_DEFAULT, GSM7_MALAYALAM, []byte{0x42}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := EncodeGSM7Lang(tt.text, tt.single, tt.locking)
+			if err != nil {
+				t.Fatalf("EncodeGSM7Lang() error = %v", err)
+			}
+			if tt.want != nil && !bytes.Equal(b, tt.want) {
+				t.Errorf("EncodeGSM7Lang() = %x, want %x", b, tt.want)
+			}
+			text, err := DecodeGSM7Lang(b, tt.single, tt.locking)
+			if err != nil || text != tt.text {
+				t.Errorf("DecodeGSM7Lang() = %q, %v, want %q", text, err, tt.text)
+			}
+		})
+	}
+}
+
+func TestGSM7LangErrors(t *testing.T) {
+	if _, err := EncodeGSM7Lang("ğ", GSM7_DEFAULT, GSM7_DEFAULT); err != ErrInvalidCharacter {
+		t.Errorf("EncodeGSM7Lang() error = %v, want %v", err, ErrInvalidCharacter)
+	}
+	if _, err := EncodeGSM7Lang("a", 0x7F, GSM7_DEFAULT); err != ErrInvalidCharacter {
+		t.Errorf("EncodeGSM7Lang() with unknown table error = %v, want %v", err, ErrInvalidCharacter)
+	}
+	// 3GPP 表中没有收录的较新 Unicode 字符
+	if _, err := EncodeGSM7Lang("\u0D29", GSM7_DEFAULT, GSM7_MALAYALAM); err != ErrInvalidCharacter {
+		t.Errorf("EncodeGSM7Lang() with unlisted character error = %v, want %v", err, ErrInvalidCharacter)
+	}
+	if _, err := DecodeGSM7Lang([]byte{'a', 0x1B}, GSM7_DEFAULT, GSM7_DEFAULT); err != ErrInvalidByte {
+		t.Errorf("DecodeGSM7Lang() with trailing escape error = %v, want %v", err, ErrInvalidByte)
+	}
+}
+
+func TestEncodeGSM7Auto(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		langs []uint8
+		udh   []IEI
+	}{
+		{"default alphabet", "hello", nil, nil},
+		{"turkish", "Günaydın, nasılsın?", nil, []IEI{IEI_NATIONAL_LOCKING_SHIFT}},
+		{"hindi", "नमस्ते दुनिया", []uint8{GSM7_HINDI}, []IEI{IEI_NATIONAL_LOCKING_SHIFT}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			septets, h, err := EncodeGSM7Auto(tt.text, nil, tt.langs...)
+			if err != nil {
+				t.Fatalf("EncodeGSM7Auto() error = %v", err)
+			}
+			for _, id := range tt.udh {
+				if h.Get(id) == nil {
+					t.Errorf("UDH %v lacks IEI 0x%02x", h, id)
+				}
+			}
+			if tt.udh == nil && h != nil {
+				t.Errorf("UDH = %v, want nil", h)
+			}
+			text, err := DecodeUserData(ASCII, h, septets)
+			if err != nil || text != tt.text {
+				t.Errorf("DecodeUserData() = %q, %v, want %q", text, err, tt.text)
+			}
+		})
+	}
+
+	if _, _, err := EncodeGSM7Auto("你好", nil); err != ErrInvalidCharacter {
+		t.Errorf("EncodeGSM7Auto() error = %v, want %v", err, ErrInvalidCharacter)
+	}
+}
@@ -0,0 +1,157 @@
+package pkg
+
+import (
+	"fmt"
+	"time"
+)
+
+// 短信编译为 PDU 的方式
+type MessageMode uint8
+
+const (
+	MESSAGE_SUBMIT_SM MessageMode = iota // submit_sm, 超长时按 Profile 的拆分器拆分为长短信
+	MESSAGE_PAYLOAD                      // 一条 submit_sm, 内容放在 message_payload 可选参数中, 由 SMSC 拆分
+	MESSAGE_DATA_SM                      // 一条 data_sm, 内容放在 message_payload 可选参数中
+)
+
+// 待发送的短信, 编译为 PDU 时除编码相关的字段外均保持原样
+type Message struct {
+	ServiceType     string
+	SourceAddrTON   uint8
+	SourceAddrNPI   uint8
+	SourceAddr      string
+	DestAddrTON     uint8
+	DestAddrNPI     uint8
+	DestinationAddr string
+
+	Text       string // UTF-8 文本
+	Payload    []byte // 二进制内容, 不为 nil 时忽略 Text
+	DataCoding uint8  // 发送文本时为 0 则按 Profile 自动选择; 发送二进制内容时不是 8 位二进制编码则使用 BINARY
+	UDH        UDH    // 每条都需要携带的其他信息单元, 如应用端口
+
+	EsmClass             uint8  // 消息模式与类型, UDHI 标识按需设置
+	ProtocolID           uint8  // data_sm 不携带
+	PriorityFlag         uint8  // data_sm 不携带
+	ScheduleDeliveryTime string // SMPP 时间格式, 见 AbsoluteTime 与 RelativeTime; data_sm 不携带
+	ValidityPeriod       string // 同上
+	RegisteredDelivery   uint8  // 状态报告要求, 如 NEED_REPORT
+	ReplaceIfPresentFlag uint8  // data_sm 不携带
+	SmDefaultMsgID       uint8  // data_sm 不携带
+	Options              Options
+
+	Mode MessageMode
+}
+
+// SMPP 绝对时间格式 YYMMDDhhmmsstnnp
+func AbsoluteTime(t time.Time) string {
+	_, offset := t.Zone()
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	return fmt.Sprintf("%s%d%02d%c", t.Format("060102150405"), t.Nanosecond()/1e8, offset/900, sign)
+}
+
+// SMPP 相对时间格式 YYMMDDhhmmss000R, 按每年 365 天、每月 30 天换算
+func RelativeTime(d time.Duration) string {
+	s := int64(d / time.Second)
+	const day = 24 * 3600
+	yy, s := s/(365*day), s%(365*day)
+	mm, s := s/(30*day), s%(30*day)
+	dd, s := s/day, s%day
+	return fmt.Sprintf("%02d%02d%02d%02d%02d%02d000R", yy, mm, dd, s/3600, s%3600/60, s%60)
+}
+
+func (m *Message) template() *SmppSubmitReqPkt {
+	return &SmppSubmitReqPkt{
+		ServiceType:          m.ServiceType,
+		SourceAddrTON:        m.SourceAddrTON,
+		SourceAddrNPI:        m.SourceAddrNPI,
+		SourceAddr:           m.SourceAddr,
+		DestAddrTON:          m.DestAddrTON,
+		DestAddrNPI:          m.DestAddrNPI,
+		DestinationAddr:      m.DestinationAddr,
+		EsmClass:             m.EsmClass &^ SM_UDH_GSM,
+		ProtocolID:           m.ProtocolID,
+		PriorityFlag:         m.PriorityFlag,
+		ScheduleDeliveryTime: m.ScheduleDeliveryTime,
+		ValidityPeriod:       m.ValidityPeriod,
+		RegisteredDelivery:   m.RegisteredDelivery,
+		ReplaceIfPresentFlag: m.ReplaceIfPresentFlag,
+		DataCoding:           m.DataCoding,
+		SmDefaultMsgID:       m.SmDefaultMsgID,
+		ShortMessage:         m.Text,
+	}
+}
+
+// 携带 message_payload 的可选参数
+func (m *Message) payloadOptions(payload []byte) Options {
+	opts := make(Options, len(m.Options)+1)
+	for tag, tlv := range m.Options {
+		opts[tag] = tlv
+	}
+	opts[TAG_MessagePayload] = NewTLV(TAG_MessagePayload, payload)
+	return opts
+}
+
+// 按 m.Mode 编译为需要依次发送的 PDU, 为 *SmppSubmitReqPkt 或 *SmppDataReqPkt
+func (pf *Profile) GetMessagePkgs(m *Message) ([]Packer, error) {
+	packets := make([]Packer, 0)
+	tmpl := m.template()
+
+	h := m.UDH[:len(m.UDH):len(m.UDH)]
+	var content []byte
+	if m.Payload != nil {
+		if !DCS(tmpl.DataCoding).Binary() {
+			tmpl.DataCoding = BINARY
+		}
+		content = m.Payload
+	} else {
+		dc, eh, c, err := pf.encodeText(tmpl.DataCoding, tmpl.ShortMessage)
+		if err != nil {
+			return packets, err
+		}
+		tmpl.DataCoding, h, content = dc, append(h, eh...), c
+	}
+
+	if m.Mode == MESSAGE_SUBMIT_SM {
+		pkts, err := pf.split(tmpl, tmpl.DataCoding, h, content, m.Options)
+		if err != nil {
+			return packets, err
+		}
+		for _, p := range pkts {
+			packets = append(packets, p)
+		}
+		return packets, nil
+	}
+
+	esmClass := tmpl.EsmClass
+	if len(h) > 0 {
+		esmClass |= SM_UDH_GSM
+	}
+	opts := m.payloadOptions(pf.ShortMessage(tmpl.DataCoding, h, content))
+
+	if m.Mode == MESSAGE_PAYLOAD {
+		p := *tmpl
+		p.EsmClass, p.ShortMessage, p.SmLength, p.Options = esmClass, "", 0, opts
+		return append(packets, &p), nil
+	}
+
+	return append(packets, &SmppDataReqPkt{
+		ServiceType:        tmpl.ServiceType,
+		SourceAddrTON:      tmpl.SourceAddrTON,
+		SourceAddrNPI:      tmpl.SourceAddrNPI,
+		SourceAddr:         tmpl.SourceAddr,
+		DestAddrTON:        tmpl.DestAddrTON,
+		DestAddrNPI:        tmpl.DestAddrNPI,
+		DestinationAddr:    tmpl.DestinationAddr,
+		EsmClass:           esmClass,
+		RegisteredDelivery: tmpl.RegisteredDelivery,
+		DataCoding:         tmpl.DataCoding,
+		Options:            opts,
+	}), nil
+}
+
+func GetMessagePkgs(m *Message) ([]Packer, error) {
+	return DefaultProfile.GetMessagePkgs(m)
+}
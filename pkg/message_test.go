@@ -0,0 +1,157 @@
+package pkg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetMsgPkgsCopiesFields(t *testing.T) {
+	ref := NewTLV(TAG_UserMessageReference, []byte{0x00, 0x01})
+	pkg := &SmppSubmitReqPkt{
+		ServiceType:          "CMT",
+		SourceAddr:           "10086",
+		DestinationAddr:      "8613800000000",
+		EsmClass:             0x03,
+		ProtocolID:           0x7F,
+		PriorityFlag:         2,
+		ScheduleDeliveryTime: "000001000000000R",
+		ValidityPeriod:       "000002000000000R",
+		RegisteredDelivery:   NEED_REPORT,
+		DataCoding:           ASCII,
+		ShortMessage:         strings.Repeat("你好", 50),
+		Options:              Options{TAG_UserMessageReference: ref},
+	}
+
+	packets, err := DefaultProfile.GetMsgPkgs(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packets) != 2 {
+		t.Fatalf("got %d packets, want 2", len(packets))
+	}
+	if pkg.DataCoding != ASCII || len(pkg.Options) != 1 {
+		t.Errorf("caller's submit_sm was modified: data_coding %d, %d options", pkg.DataCoding, len(pkg.Options))
+	}
+
+	for _, p := range packets {
+		if p.ServiceType != pkg.ServiceType || p.SourceAddr != pkg.SourceAddr || p.DestinationAddr != pkg.DestinationAddr ||
+			p.ProtocolID != pkg.ProtocolID || p.PriorityFlag != pkg.PriorityFlag ||
+			p.ScheduleDeliveryTime != pkg.ScheduleDeliveryTime || p.ValidityPeriod != pkg.ValidityPeriod ||
+			p.RegisteredDelivery != pkg.RegisteredDelivery {
+			t.Errorf("segment lost fields: %+v", p)
+		}
+		if p.EsmClass != 0x03|SM_UDH_GSM {
+			t.Errorf("esm_class = %#02x, want %#02x", p.EsmClass, 0x03|SM_UDH_GSM)
+		}
+		if p.DataCoding != UCS2 {
+			t.Errorf("data_coding = %d, want %d", p.DataCoding, UCS2)
+		}
+		if p.Options[TAG_UserMessageReference] != ref {
+			t.Errorf("segment lost the user_message_reference option")
+		}
+	}
+}
+
+func TestGetMessagePkgs(t *testing.T) {
+	port := UDH{NewPortIE(PORT_VCARD, PORT_VCARD, true)}
+	tests := []struct {
+		name       string
+		m          Message
+		count      int
+		dataCoding uint8
+		data       bool // data_sm
+		payload    bool // message_payload
+		udhi       bool
+	}{
+		{"short text", Message{Text: "hello"}, 1, ASCII, false, false, false},
+		{"long text", Message{Text: strings.Repeat("a", 200)}, 2, ASCII, false, false, true},
+		{"binary with port", Message{Payload: []byte("BEGIN:VCARD"), UDH: port}, 1, BINARY, false, false, true},
+		{"binary keeps 8 bit class", Message{Payload: []byte{1}, DataCoding: 0xF6}, 1, 0xF6, false, false, false},
+		{"binary replaces latin1", Message{Payload: []byte{1}, DataCoding: LATIN1}, 1, BINARY, false, false, false},
+		{"message_payload", Message{Text: strings.Repeat("你", 200), Mode: MESSAGE_PAYLOAD}, 1, UCS2, false, true, false},
+		{"data_sm", Message{Text: strings.Repeat("a", 300), Mode: MESSAGE_DATA_SM}, 1, ASCII, true, true, false},
+		{"data_sm with port", Message{Payload: []byte{1, 2}, UDH: port, Mode: MESSAGE_DATA_SM}, 1, BINARY, true, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.m.DestinationAddr = "1"
+			udhLen := len(tt.m.UDH)
+			packets, err := GetMessagePkgs(&tt.m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(packets) != tt.count {
+				t.Fatalf("got %d packets, want %d", len(packets), tt.count)
+			}
+			if len(tt.m.UDH) != udhLen {
+				t.Errorf("caller's UDH was modified")
+			}
+
+			for _, packet := range packets {
+				var (
+					dataCoding, esmClass uint8
+					opts                 Options
+					sm                   string
+				)
+				switch p := packet.(type) {
+				case *SmppSubmitReqPkt:
+					if tt.data {
+						t.Fatalf("got submit_sm, want data_sm")
+					}
+					dataCoding, esmClass, opts, sm = p.DataCoding, p.EsmClass, p.Options, p.ShortMessage
+				case *SmppDataReqPkt:
+					if !tt.data {
+						t.Fatalf("got data_sm, want submit_sm")
+					}
+					dataCoding, esmClass, opts = p.DataCoding, p.EsmClass, p.Options
+				default:
+					t.Fatalf("unexpected packet %T", packet)
+				}
+
+				if dataCoding != tt.dataCoding {
+					t.Errorf("data_coding = %#02x, want %#02x", dataCoding, tt.dataCoding)
+				}
+				if (esmClass&SM_UDH_GSM != 0) != tt.udhi {
+					t.Errorf("esm_class = %#02x, udhi want %v", esmClass, tt.udhi)
+				}
+				payload := opts[TAG_MessagePayload]
+				if (payload != nil) != tt.payload {
+					t.Fatalf("message_payload present = %v, want %v", payload != nil, tt.payload)
+				}
+				if payload != nil {
+					if sm != "" {
+						t.Errorf("short_message should be empty with message_payload")
+					}
+					sm = string(payload.Value)
+				}
+				if tt.m.Payload != nil {
+					if _, ud, err := splitUDH(esmClass, sm); err != nil || !bytes.Equal(ud, tt.m.Payload) {
+						t.Errorf("user data = %x, %v, want %x", ud, err, tt.m.Payload)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSMPPTime(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"absolute utc", AbsoluteTime(time.Date(2020, 1, 2, 3, 4, 5, 600e6, time.UTC)), "200102030405600+"},
+		{"absolute east", AbsoluteTime(time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("CST", 8*3600))), "200102030405032+"},
+		{"absolute west", AbsoluteTime(time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("EST", -5*3600))), "200102030405020-"},
+		{"relative", RelativeTime(26*time.Hour + 90*time.Second), "000001020130000R"},
+		{"relative month and year", RelativeTime((365 + 31) * 24 * time.Hour), "010101000000000R"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+}
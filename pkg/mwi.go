@@ -0,0 +1,107 @@
+package pkg
+
+import "errors"
+
+var ErrMWIDiscardUCS2 = errors.New("mwi: discard message group only supports GSM 7 bit")
+
+// 消息等待指示的携带方式
+type MWIMode uint8
+
+const (
+	MWI_MODE_TLV MWIMode = iota // ms_msg_wait_facilities 与 number_of_messages 可选参数
+	MWI_MODE_DCS                // data_coding 消息等待指示编码组, 不携带消息数
+	MWI_MODE_UDH                // 用户数据头中的特殊短信指示信息单元
+)
+
+// 消息等待指示
+type MessageWaiting struct {
+	Type   uint8 // MWI_VOICEMAIL、MWI_FAX、MWI_EMAIL 或 MWI_OTHER
+	Active bool  // 设置或清除指示
+	Count  uint8 // 等待的消息数, 清除时为 0
+	Store  bool  // 接收方是否保存随指示发送的短信
+}
+
+func (m *MessageWaiting) count() uint8 {
+	if !m.Active {
+		return 0
+	}
+	return m.Count
+}
+
+// ms_msg_wait_facilities 可选参数, bit7 为是否有消息等待, bit0-1 为指示类型
+func (m *MessageWaiting) options() Options {
+	b := m.Type & 0x03
+	if m.Active {
+		b |= 0x80
+	}
+	return Options{
+		TAG_MsMsgWaitFacilities: NewTLV(TAG_MsMsgWaitFacilities, []byte{b}),
+		TAG_NumberOfMessages:    NewTLV(TAG_NumberOfMessages, []byte{m.count()}),
+	}
+}
+
+// 生成携带消息等待指示的 submit_sm, pkg.ShortMessage 为随指示发送的文本, 可以为空
+func (pf *Profile) GetMWIMsgPkgs(pkg *SmppSubmitReqPkt, mode MWIMode, mwi *MessageWaiting) ([]*SmppSubmitReqPkt, error) {
+	if mode == MWI_MODE_DCS {
+		content, err := Encode7Bit(pkg.ShortMessage)
+		dataCoding := uint8(ASCII)
+		if err != nil {
+			if !mwi.Store {
+				return make([]*SmppSubmitReqPkt, 0), ErrMWIDiscardUCS2
+			}
+			if content, err = pf.Encode(UCS2, pkg.ShortMessage); err != nil {
+				return make([]*SmppSubmitReqPkt, 0), err
+			}
+			dataCoding = UCS2
+		}
+		dcs := uint8(NewMWIDCS(dataCoding, mwi.Store, mwi.Active, mwi.Type))
+		return pf.split(pkg, dcs, nil, content, nil)
+	}
+
+	dataCoding, udh, content, err := pf.encodeText(pkg.DataCoding, pkg.ShortMessage)
+	if err != nil {
+		return make([]*SmppSubmitReqPkt, 0), err
+	}
+	if mode == MWI_MODE_TLV {
+		return pf.split(pkg, dataCoding, udh, content, mwi.options())
+	}
+	h := UDH{NewSpecialSMSIE(mwi.Store, mwi.Type&0x03, mwi.count())}
+	return pf.split(pkg, dataCoding, append(h, udh...), content, nil)
+}
+
+func GetMWIMsgPkgs(pkg *SmppSubmitReqPkt, mode MWIMode, mwi *MessageWaiting) ([]*SmppSubmitReqPkt, error) {
+	return DefaultProfile.GetMWIMsgPkgs(pkg, mode, mwi)
+}
+
+// 依次从可选参数、data_coding 与用户数据头中解析消息等待指示
+func parseMessageWaiting(dataCoding, esmClass uint8, sm string, opts Options) []*MessageWaiting {
+	var mwis []*MessageWaiting
+
+	if tlv := opts[TAG_MsMsgWaitFacilities]; tlv != nil && len(tlv.Value) == 1 {
+		m := &MessageWaiting{Type: tlv.Value[0] & 0x03, Active: tlv.Value[0]&0x80 != 0, Store: true}
+		if n := opts[TAG_NumberOfMessages]; n != nil && len(n.Value) == 1 {
+			m.Count = n.Value[0]
+		}
+		mwis = append(mwis, m)
+	}
+
+	if kind, active, store, ok := DCS(dataCoding).MWI(); ok {
+		mwis = append(mwis, &MessageWaiting{Type: kind, Active: active, Store: store})
+	}
+
+	if h, _, err := splitUDH(esmClass, sm); err == nil {
+		for _, s := range h.SpecialSMS() {
+			mwis = append(mwis, &MessageWaiting{Type: s.Type & 0x03, Active: s.Count > 0, Count: s.Count, Store: s.Store})
+		}
+	}
+	return mwis
+}
+
+// 短信中携带的所有消息等待指示, 没有时为 nil
+func (p *SmppDeliverReqPkt) MessageWaiting() []*MessageWaiting {
+	return parseMessageWaiting(p.DataCoding, p.EsmClass, p.ShortMessage, p.Options)
+}
+
+func (p *SmppSubmitReqPkt) MessageWaiting() []*MessageWaiting {
+	return parseMessageWaiting(p.DataCoding, p.EsmClass, p.ShortMessage, p.Options)
+}
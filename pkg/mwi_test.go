@@ -0,0 +1,64 @@
+package pkg
+
+import "testing"
+
+func TestGetMWIMsgPkgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       MWIMode
+		text       string
+		mwi        MessageWaiting
+		dataCoding uint8
+		wantErr    error
+	}{
+		{"tlv", MWI_MODE_TLV, "you have mail", MessageWaiting{Type: MWI_EMAIL, Active: true, Count: 3, Store: true}, ASCII, nil},
+		{"dcs discard", MWI_MODE_DCS, "", MessageWaiting{Type: MWI_VOICEMAIL, Active: true}, 0xC8, nil},
+		{"dcs store", MWI_MODE_DCS, "voicemail", MessageWaiting{Type: MWI_VOICEMAIL, Active: false, Store: true}, 0xD0, nil},
+		{"dcs store ucs2", MWI_MODE_DCS, "语音信箱", MessageWaiting{Type: MWI_FAX, Active: true, Store: true}, 0xE9, nil},
+		{"dcs discard ucs2", MWI_MODE_DCS, "语音信箱", MessageWaiting{Type: MWI_FAX, Active: true}, 0, ErrMWIDiscardUCS2},
+		{"udh", MWI_MODE_UDH, "", MessageWaiting{Type: MWI_OTHER, Active: true, Count: 9, Store: true}, ASCII, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mwi := tt.mwi
+			packets, err := GetMWIMsgPkgs(&SmppSubmitReqPkt{DestinationAddr: "1", ShortMessage: tt.text}, tt.mode, &mwi)
+			if err != tt.wantErr {
+				t.Fatalf("GetMWIMsgPkgs() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(packets) != 1 {
+				t.Fatalf("got %d packets, want 1", len(packets))
+			}
+			p := packets[0]
+			if p.DataCoding != tt.dataCoding {
+				t.Errorf("data_coding = %#02x, want %#02x", p.DataCoding, tt.dataCoding)
+			}
+
+			got := p.MessageWaiting()
+			if len(got) != 1 {
+				t.Fatalf("MessageWaiting() = %v, want 1 indication", got)
+			}
+			want := mwi
+			if !want.Active {
+				want.Count = 0
+			}
+			if tt.mode == MWI_MODE_TLV {
+				want.Store = true
+			}
+			if tt.mode == MWI_MODE_DCS {
+				want.Count = 0
+			}
+			if *got[0] != want {
+				t.Errorf("MessageWaiting() = %+v, want %+v", *got[0], want)
+			}
+
+			text, err := DefaultProfile.DecodeSubmit(p)
+			if err != nil || text != tt.text {
+				t.Errorf("DecodeSubmit() = %q, %v, want %q", text, err, tt.text)
+			}
+		})
+	}
+}
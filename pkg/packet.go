@@ -38,17 +38,18 @@ func (w *pkgWriter) Bytes() ([]byte, error) {
 	return (w.b.Bytes())[:l], nil
 }
 
-func (w *pkgWriter) WriteByte(b byte) {
+func (w *pkgWriter) WriteByte(b byte) error {
 	if w.err != nil {
-		return
+		return w.err
 	}
 
 	err := w.b.WriteByte(b)
 	if err != nil {
 		w.err = NewOpError(err,
 			fmt.Sprintf("pkgWriter.WriteByte writes: %x", b))
-		return
+		return w.err
 	}
+	return nil
 }
 
 func (w *pkgWriter) WriteBytes(b []byte) {
@@ -146,7 +147,7 @@ func (r *pkgReader) Len() int {
 	return r.rb.Len()
 }
 
-func (r *pkgReader) ReadByte() byte {
+func (r *pkgReader) ReadUint8() byte {
 	if r.err != nil {
 		return 0
 	}
@@ -154,7 +155,7 @@ func (r *pkgReader) ReadByte() byte {
 	b, err := r.rb.ReadByte()
 	if err != nil {
 		r.err = NewOpError(err,
-			"pkgReader.ReadByte")
+			"pkgReader.ReadUint8")
 		return 0
 	}
 	return b
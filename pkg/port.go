@@ -0,0 +1,91 @@
+package pkg
+
+import "encoding/binary"
+
+// 常用的应用端口, 见 3GPP TS 23.040 9.2.3.24.4 与 WAP WDP
+const (
+	PORT_WAP_PUSH      uint16 = 2948 // WAP Push, 无连接 WSP
+	PORT_WAP_PUSH_SEC  uint16 = 2949 // WAP Push, 无连接 WSP (WTLS)
+	PORT_WAP_WSP       uint16 = 9200 // 无连接 WSP
+	PORT_WAP_WSP_SEC   uint16 = 9202 // 无连接 WSP (WTLS)
+	PORT_VCARD         uint16 = 9204
+	PORT_VCALENDAR     uint16 = 9205
+	PORT_VCARD_SEC     uint16 = 9206
+	PORT_VCALENDAR_SEC uint16 = 9207
+)
+
+// 应用端口的携带方式
+type PortMode uint8
+
+const (
+	PORT_UDH PortMode = iota // 用户数据头中的 16 位应用端口信息单元
+	PORT_TLV                 // source_port、destination_port 可选参数
+)
+
+func portOptions(dst, src uint16) Options {
+	return Options{
+		TAG_DestinationPort: NewTLV(TAG_DestinationPort, packUi16(dst)),
+		TAG_SourcePort:      NewTLV(TAG_SourcePort, packUi16(src)),
+	}
+}
+
+// 从可选参数中取出目的端口与源端口
+func optionPorts(opts Options) (dst, src uint16, ok bool) {
+	d, s := opts[TAG_DestinationPort], opts[TAG_SourcePort]
+	if d == nil || len(d.Value) != 2 {
+		return 0, 0, false
+	}
+	if s != nil && len(s.Value) == 2 {
+		src = binary.BigEndian.Uint16(s.Value)
+	}
+	return binary.BigEndian.Uint16(d.Value), src, true
+}
+
+// 将二进制内容 payload 拆分为发往应用端口 dst 的 submit_sm, pkg 提供地址等参数
+// data_coding 不是 8 位二进制编码时使用 BINARY, LATIN1 等文本编码也会被替换; 拆分后每条都携带应用端口
+func (pf *Profile) GetPortMsgPkgs(pkg *SmppSubmitReqPkt, dst, src uint16, mode PortMode, payload []byte) ([]*SmppSubmitReqPkt, error) {
+	dataCoding := pkg.DataCoding
+	if !DCS(dataCoding).Binary() {
+		dataCoding = BINARY
+	}
+	if mode == PORT_TLV {
+		return pf.split(pkg, dataCoding, nil, payload, portOptions(dst, src))
+	}
+	return pf.split(pkg, dataCoding, UDH{NewPortIE(dst, src, true)}, payload, nil)
+}
+
+func GetPortMsgPkgs(pkg *SmppSubmitReqPkt, dst, src uint16, mode PortMode, payload []byte) ([]*SmppSubmitReqPkt, error) {
+	return DefaultProfile.GetPortMsgPkgs(pkg, dst, src, mode, payload)
+}
+
+// 目的端口与源端口, 优先取用户数据头, 其次为 destination_port、source_port 可选参数
+func (p *SmppDeliverReqPkt) Ports() (dst, src uint16, ok bool) {
+	if h, _, err := p.UDH(); err == nil {
+		if dst, src, ok = h.Ports(); ok {
+			return
+		}
+	}
+	return optionPorts(p.Options)
+}
+
+func (p *SmppSubmitReqPkt) Ports() (dst, src uint16, ok bool) {
+	if h, _, err := p.UDH(); err == nil {
+		if dst, src, ok = h.Ports(); ok {
+			return
+		}
+	}
+	return optionPorts(p.Options)
+}
+
+// 长短信的目的端口与源端口, 取法同 SmppDeliverReqPkt.Ports
+func (m *LongMessage) Ports() (dst, src uint16, ok bool) {
+	if dst, src, ok = m.UDH.Ports(); ok {
+		return
+	}
+	for _, p := range m.Parts {
+		if p != nil {
+			return optionPorts(p.Options)
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,176 @@
+package pkg
+
+import "fmt"
+
+// 对端 SMSC 的编码习惯, 同一连接上 submit_sm 的编码与 deliver_sm 的解码都按此处理
+type Profile struct {
+	Packed7Bit bool       // GSM 7 bit 内容按 septet 压缩传输, 有用户数据头时从 septet 边界开始
+	Segmenter  *Segmenter // 长短信拆分器, 为 nil 时使用 DefaultSegmenter
+
+	// data_coding 0 的实际字符集, 取 ASCII (GSM 7 bit)、IA5 或 LATIN1
+	DefaultAlphabet uint8
+	// 按 data_coding 覆盖全局注册的编解码, 用于对端对某些取值有不同的解释
+	Codecs map[uint8]Codec
+
+	// 自动选择编码时的候选
+	NoLanguageShift bool    // 不使用国家语言转义表
+	Languages       []uint8 // 可用的国家语言转义表, 为空时可用全部
+	Latin1          bool    // 可用 Latin-1 (data_coding 3)
+
+	// 不为 nil 时, 原本需要 UCS2 的文本先尝试替换为 GSM 7 bit 字符, 替换后不多于 UCS2 的条数时使用替换后的文本
+	Transliterator *Transliterator
+	// 发生替换时回调, 用于记录或审核替换的内容
+	OnTransliterate func(text string, subs []Substitution)
+}
+
+// 每个 septet 占一个字节, 即 SMPP 默认的格式
+var DefaultProfile = &Profile{}
+
+func (pf *Profile) segmenter() *Segmenter {
+	if pf.Segmenter == nil {
+		return DefaultSegmenter
+	}
+	return pf.Segmenter
+}
+
+// 实际的字符集, 决定拆分长度以及是否为 GSM 7 bit
+func (pf *Profile) alphabet(msgFmt uint8) uint8 {
+	if msgFmt == ASCII {
+		return pf.DefaultAlphabet
+	}
+	return msgFmt
+}
+
+func (pf *Profile) gsm7(msgFmt uint8) bool {
+	return alphabetOf(pf.alphabet(msgFmt)) == alphabetGSM7
+}
+
+func (pf *Profile) packed(msgFmt uint8) bool {
+	return pf.Packed7Bit && pf.gsm7(msgFmt)
+}
+
+func (pf *Profile) codec(msgFmt uint8) (Codec, bool) {
+	if c, ok := pf.Codecs[msgFmt]; ok {
+		return c, true
+	}
+	return GetCodec(pf.alphabet(msgFmt))
+}
+
+// 按对端的解释编码 UTF-8 文本, GSM 7 bit 为每字节一个 septet
+func (pf *Profile) Encode(msgFmt uint8, text string) ([]byte, error) {
+	c, ok := pf.codec(msgFmt)
+	if !ok {
+		return nil, fmt.Errorf("data_coding: met an unexpected data_coding [%d]", msgFmt)
+	}
+	return c.Encode(text)
+}
+
+// 按对端的解释解码用户数据, GSM 7 bit 内容按用户数据头中的国家语言信息单元解码
+func (pf *Profile) DecodeUserData(msgFmt uint8, h UDH, ud []byte) (string, error) {
+	if _, ok := pf.Codecs[msgFmt]; !ok && pf.gsm7(msgFmt) {
+		single, locking := h.NationalLanguage()
+		return DecodeGSM7Lang(ud, single, locking)
+	}
+	c, ok := pf.codec(msgFmt)
+	if !ok {
+		return "", fmt.Errorf("data_coding: met an unexpected data_coding [%d]", msgFmt)
+	}
+	return c.Decode(ud)
+}
+
+// 按 SMSC 的格式拼接用户数据头与用户数据
+func (pf *Profile) ShortMessage(msgFmt uint8, h UDH, payload []byte) []byte {
+	if pf.packed(msgFmt) {
+		payload = Pack7Bit(payload, GSM7FillBits(h.Len()))
+	}
+	return h.Join(payload)
+}
+
+// 解析 short_message 为用户数据头与用户数据, GSM 7 bit 内容统一解压为每字节一个 septet
+func (pf *Profile) UserData(msgFmt, esmClass uint8, sm []byte) (UDH, []byte, error) {
+	h, ud, err := splitUDH(esmClass, string(sm))
+	if err != nil {
+		return nil, nil, err
+	}
+	if pf.packed(msgFmt) {
+		ud = Unpack7Bit(ud, GSM7FillBits(h.Len()))
+	}
+	return h, ud, nil
+}
+
+// 解码 short_message 为 UTF-8 文本, 用户数据头不包含在内
+func (pf *Profile) Decode(msgFmt, esmClass uint8, sm []byte) (string, error) {
+	h, ud, err := pf.UserData(msgFmt, esmClass, sm)
+	if err != nil {
+		return "", err
+	}
+	return pf.DecodeUserData(msgFmt, h, ud)
+}
+
+func (pf *Profile) DecodeDeliver(p *SmppDeliverReqPkt) (string, error) {
+	return pf.Decode(p.DataCoding, p.EsmClass, []byte(p.ShortMessage))
+}
+
+func (pf *Profile) DecodeSubmit(p *SmppSubmitReqPkt) (string, error) {
+	return pf.Decode(p.DataCoding, p.EsmClass, []byte(p.ShortMessage))
+}
+
+// 编码并拆分 submit_sm, 按拆分器的拼接方式设置用户数据头或 SAR 可选参数
+// 每条都复制 pkg 的地址、优先级、有效期、状态报告要求与可选参数等字段, pkg 不会被修改
+func (pf *Profile) GetMsgPkgs(pkg *SmppSubmitReqPkt) ([]*SmppSubmitReqPkt, error) {
+	dataCoding, udh, content, err := pf.encodeText(pkg.DataCoding, pkg.ShortMessage)
+	if err != nil {
+		return make([]*SmppSubmitReqPkt, 0), err
+	}
+	return pf.split(pkg, dataCoding, udh, content, nil)
+}
+
+// 编码 text, data_coding 为 0 时自动选择编码, 返回实际使用的 data_coding
+func (pf *Profile) encodeText(dataCoding uint8, text string) (uint8, UDH, []byte, error) {
+	if dataCoding != ASCII {
+		content, err := pf.Encode(dataCoding, text)
+		return dataCoding, nil, content, err
+	}
+
+	e, err := pf.SelectEncoding(text)
+	if err != nil {
+		return dataCoding, nil, nil, err
+	}
+	if len(e.Substitutions) > 0 && pf.OnTransliterate != nil {
+		pf.OnTransliterate(text, e.Substitutions)
+	}
+	return e.DataCoding, e.UDH, e.Content, nil
+}
+
+// 将按 dataCoding 编码后的内容拆分为多条 submit_sm, 除内容与编码相关的字段外均复制自 pkg, pkg 不会被修改
+// h 与 opts 为每条都需要携带的信息单元与可选参数
+func (pf *Profile) split(pkg *SmppSubmitReqPkt, dataCoding uint8, h UDH, content []byte, opts Options) ([]*SmppSubmitReqPkt, error) {
+	packets := make([]*SmppSubmitReqPkt, 0)
+	segments, err := pf.segmenter().Split(pkg.DestinationAddr, pf.alphabet(dataCoding), h, content)
+	if err != nil {
+		return packets, err
+	}
+
+	for _, s := range segments {
+		chunk := pf.ShortMessage(dataCoding, s.UDH, s.Payload)
+		p := *pkg
+		p.EsmClass = pkg.EsmClass &^ SM_UDH_GSM
+		if len(s.UDH) > 0 {
+			p.EsmClass |= SM_UDH_GSM
+		}
+		p.DataCoding = dataCoding
+		p.SmLength = uint8(len(chunk))
+		p.ShortMessage = string(chunk)
+		p.SequenceNum = 0
+
+		segOpts := s.Options()
+		p.Options = make(Options, len(pkg.Options)+len(segOpts)+len(opts))
+		for _, o := range []Options{pkg.Options, segOpts, opts} {
+			for tag, tlv := range o {
+				p.Options[tag] = tlv
+			}
+		}
+		packets = append(packets, &p)
+	}
+	return packets, nil
+}
@@ -0,0 +1,132 @@
+package pkg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProfileShortMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		pf         *Profile
+		dataCoding uint8
+		h          UDH
+		payload    []byte
+		want       []byte
+	}{
+		{
+			name:    "unpacked gsm7",
+			pf:      &Profile{},
+			payload: []byte("hi"),
+			want:    []byte("hi"),
+		},
+		{
+			name:    "packed gsm7",
+			pf:      &Profile{Packed7Bit: true},
+			payload: []byte("hellohello"),
+			want:    []byte{0xE8, 0x32, 0x9B, 0xFD, 0x46, 0x97, 0xD9, 0xEC, 0x37},
+		},
+		{
+			name:    "packed after concat 8 starts on a septet boundary",
+			pf:      &Profile{Packed7Bit: true},
+			h:       UDH{NewConcatIE(1, 2, 1, false)},
+			payload: []byte("hi"),
+			want:    []byte{0x05, 0x00, 0x03, 0x01, 0x02, 0x01, 0xD0, 0x69},
+		},
+		{
+			name:       "ucs2 is never packed",
+			pf:         &Profile{Packed7Bit: true},
+			dataCoding: UCS2,
+			payload:    []byte{0x00, 0x41},
+			want:       []byte{0x00, 0x41},
+		},
+		{
+			name:    "latin1 default alphabet is not packed",
+			pf:      &Profile{Packed7Bit: true, DefaultAlphabet: LATIN1},
+			payload: []byte{0xE9},
+			want:    []byte{0xE9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := tt.pf.ShortMessage(tt.dataCoding, tt.h, tt.payload)
+			if !bytes.Equal(sm, tt.want) {
+				t.Fatalf("ShortMessage() = %x, want %x", sm, tt.want)
+			}
+
+			var esmClass uint8
+			if len(tt.h) > 0 {
+				esmClass = SM_UDH_GSM
+			}
+			h, ud, err := tt.pf.UserData(tt.dataCoding, esmClass, sm)
+			if err != nil {
+				t.Fatalf("UserData() error = %v", err)
+			}
+			if !bytes.Equal(h.Bytes(), tt.h.Bytes()) || !bytes.Equal(ud, tt.payload) {
+				t.Errorf("UserData() = %x, %x, want %x, %x", h.Bytes(), ud, tt.h.Bytes(), tt.payload)
+			}
+		})
+	}
+}
+
+func TestProfileGetMsgPkgsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		pf         *Profile
+		dataCoding uint8
+		text       string
+	}{
+		{"auto gsm7", &Profile{}, ASCII, strings.Repeat("Hello {world} ", 20)},
+		{"auto gsm7 packed", &Profile{Packed7Bit: true}, ASCII, strings.Repeat("Hello {world} ", 20)},
+		{"auto ucs2", &Profile{}, ASCII, strings.Repeat("你好, 世界. ", 20)},
+		{"auto turkish", &Profile{Packed7Bit: true}, ASCII, strings.Repeat("Günaydın ", 30)},
+		{"sar", &Profile{Segmenter: &Segmenter{Mode: CONCAT_SAR}}, ASCII, strings.Repeat("a", 400)},
+		{"packed full single ending in CR", &Profile{Packed7Bit: true}, ASCII, strings.Repeat("a", 159) + "\r"},
+		{"packed full segment ending in CR", &Profile{Packed7Bit: true}, ASCII, strings.Repeat("a", 152) + "\r" + strings.Repeat("b", 10)},
+		{"packed 16 bit ref ending in CR", &Profile{Packed7Bit: true, Segmenter: &Segmenter{Ref16: true}}, ASCII, strings.Repeat("a", 151) + "\r" + strings.Repeat("b", 10)},
+		{"packed sar ending in CR", &Profile{Packed7Bit: true, Segmenter: &Segmenter{Mode: CONCAT_SAR}}, ASCII, strings.Repeat("a", 159) + "\r" + strings.Repeat("b", 10)},
+		{"explicit ucs2", &Profile{}, UCS2, "hello"},
+		{"latin1 default alphabet", &Profile{DefaultAlphabet: LATIN1}, ASCII, strings.Repeat("àé", 100)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packets, err := tt.pf.GetMsgPkgs(&SmppSubmitReqPkt{DestinationAddr: "1", DataCoding: tt.dataCoding, ShortMessage: tt.text})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r := NewReassembler(0, nil)
+			r.SetProfile(tt.pf)
+			var msg *LongMessage
+			for _, p := range packets {
+				// 未压缩的 GSM 7 bit 内容每个 septet 占一个字节, 由 SMSC 压缩
+				if (tt.pf.packed(p.DataCoding) || !tt.pf.gsm7(p.DataCoding)) && len(p.ShortMessage) > MaxUserDataLen {
+					t.Errorf("short_message is %d bytes", len(p.ShortMessage))
+				}
+				if int(p.SmLength) != len(p.ShortMessage) {
+					t.Errorf("sm_length = %d, want %d", p.SmLength, len(p.ShortMessage))
+				}
+				msg, err = r.Add(&SmppDeliverReqPkt{
+					DestinationAddr: p.DestinationAddr,
+					EsmClass:        p.EsmClass,
+					DataCoding:      p.DataCoding,
+					ShortMessage:    p.ShortMessage,
+					Options:         p.Options,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if msg == nil {
+				t.Fatal("message was not reassembled")
+			}
+			text, err := msg.Text()
+			if err != nil || text != tt.text {
+				t.Errorf("Text() = %q, %v, want %q", text, err, tt.text)
+			}
+		})
+	}
+}
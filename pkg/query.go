@@ -48,8 +48,8 @@ func (p *SmppQueryReqPkt) Unpack(data []byte) error {
 	var r = newPkgReader(data)
 
 	p.MsgID = string(r.ReadOCString(65))
-	p.SourceAddrTON = r.ReadByte()
-	p.SourceAddrNPI = r.ReadByte()
+	p.SourceAddrTON = r.ReadUint8()
+	p.SourceAddrNPI = r.ReadUint8()
 	p.SourceAddr = string(r.ReadOCString(21))
 
 	return r.Error()
@@ -86,6 +86,7 @@ func (p *SmppQueryRespPkt) Pack(seqId uint32) ([]byte, error) {
 	header := Header{
 		CommandLength: commandLength,
 		CommandID:     uint32(SMPP_QUERY_RESP),
+		CommandStatus: uint32(p.Status),
 		SequenceNum:   seqId,
 	}
 	w.WriteHeader(header)
@@ -101,12 +102,15 @@ func (p *SmppQueryRespPkt) Pack(seqId uint32) ([]byte, error) {
 }
 
 func (p *SmppQueryRespPkt) Unpack(data []byte) error {
+	if len(data) == 0 { // 失败时可能没有消息体
+		return nil
+	}
 	var r = newPkgReader(data)
 
 	p.MsgID = string(r.ReadOCString(65))
 	p.FinalDate = string(r.ReadOCString(17))
-	p.MessageState = r.ReadByte()
-	p.ErrorCode = r.ReadByte()
+	p.MessageState = r.ReadUint8()
+	p.ErrorCode = r.ReadUint8()
 
 	return r.Error()
 }
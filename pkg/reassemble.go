@@ -0,0 +1,215 @@
+package pkg
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrSegmentInvalid = errors.New("reassemble: invalid segment number")
+
+// 默认等待收齐长短信的时间
+const DefaultReassembleTimeout = 5 * time.Minute
+
+// 拼接完成(或超时)的长短信
+type LongMessage struct {
+	SourceAddr      string
+	DestinationAddr string
+	DataCoding      uint8
+	Ref             uint16
+	Total           uint8
+	UDH             UDH                  // 第一条短信中除长短信信息单元以外的信息单元
+	Content         []byte               // 按序拼接的用户数据, 不含用户数据头, GSM 7 bit 内容每字节一个 septet
+	Parts           []*SmppDeliverReqPkt // 按序号排列, 超时回调时缺失的位置为 nil
+
+	profile *Profile
+}
+
+// 按拼接器的编码习惯解码为 UTF-8 文本
+func (m *LongMessage) Text() (string, error) {
+	pf := m.profile
+	if pf == nil {
+		pf = DefaultProfile
+	}
+	return pf.DecodeUserData(m.DataCoding, m.UDH, m.Content)
+}
+
+type reassemblyKey struct {
+	source string
+	dest   string
+	ref    uint16
+	total  uint8
+}
+
+type partial struct {
+	msg      *LongMessage
+	payloads [][]byte
+	received int
+	timer    *time.Timer
+}
+
+// 长短信拼接器, 同时支持 UDH 与 SAR 可选参数两种方式的长短信
+type Reassembler struct {
+	timeout  time.Duration
+	onExpire func(*LongMessage)
+	profile  *Profile
+
+	mu      sync.Mutex
+	pending map[reassemblyKey]*partial
+}
+
+// timeout 为从收到第一条起等待收齐的时间, 超时后丢弃并回调 onExpire (可为 nil)
+// timeout 不大于 0 时使用 DefaultReassembleTimeout, 避免未收齐的长短信一直占用内存
+func NewReassembler(timeout time.Duration, onExpire func(*LongMessage)) *Reassembler {
+	if timeout <= 0 {
+		timeout = DefaultReassembleTimeout
+	}
+	return &Reassembler{
+		timeout:  timeout,
+		onExpire: onExpire,
+		profile:  DefaultProfile,
+		pending:  make(map[reassemblyKey]*partial),
+	}
+}
+
+// 设置对端的编码习惯, 压缩的 GSM 7 bit 内容会先逐条解压再拼接
+func (r *Reassembler) SetProfile(pf *Profile) {
+	r.mu.Lock()
+	r.profile = pf
+	r.mu.Unlock()
+}
+
+// 从 deliver_sm 中取出长短信参考号、总条数、序号以及用户数据
+func concatParts(pf *Profile, p *SmppDeliverReqPkt) (ref uint16, total, seq uint8, h UDH, payload []byte, err error) {
+	h, payload, err = pf.UserData(p.DataCoding, p.EsmClass, []byte(p.ShortMessage))
+	if err != nil {
+		return
+	}
+
+	if r, t, s, ok := h.Concat(); ok {
+		rest := make(UDH, 0, len(h))
+		for _, ie := range h {
+			if ie.ID != IEI_CONCAT_8 && ie.ID != IEI_CONCAT_16 {
+				rest = append(rest, ie)
+			}
+		}
+		return r, t, s, rest, payload, nil
+	}
+
+	refTLV, totalTLV, seqTLV := p.Options[TAG_SarMsgRefNum], p.Options[TAG_SarTotalSegments], p.Options[TAG_SarSegmentSeqnum]
+	if refTLV != nil && totalTLV != nil && seqTLV != nil &&
+		len(refTLV.Value) == 2 && len(totalTLV.Value) == 1 && len(seqTLV.Value) == 1 {
+		return binary.BigEndian.Uint16(refTLV.Value), totalTLV.Value[0], seqTLV.Value[0], h, payload, nil
+	}
+
+	return 0, 1, 1, h, payload, nil
+}
+
+// 加入一条 deliver_sm
+// 收齐时返回拼接完成的长短信; 未收齐或重复收到时返回 nil; 不是长短信时直接返回只有一条的长短信
+func (r *Reassembler) Add(p *SmppDeliverReqPkt) (*LongMessage, error) {
+	r.mu.Lock()
+	pf := r.profile
+	r.mu.Unlock()
+
+	ref, total, seq, h, payload, err := concatParts(pf, p)
+	if err != nil {
+		return nil, err
+	}
+	if total == 0 || seq == 0 || seq > total {
+		return nil, ErrSegmentInvalid
+	}
+
+	if total == 1 {
+		return &LongMessage{
+			SourceAddr:      p.SourceAddr,
+			DestinationAddr: p.DestinationAddr,
+			DataCoding:      p.DataCoding,
+			Ref:             ref,
+			Total:           total,
+			UDH:             h,
+			Content:         payload,
+			Parts:           []*SmppDeliverReqPkt{p},
+			profile:         pf,
+		}, nil
+	}
+
+	key := reassemblyKey{source: p.SourceAddr, dest: p.DestinationAddr, ref: ref, total: total}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pt, ok := r.pending[key]
+	if !ok {
+		pt = &partial{
+			msg: &LongMessage{
+				SourceAddr:      p.SourceAddr,
+				DestinationAddr: p.DestinationAddr,
+				DataCoding:      p.DataCoding,
+				Ref:             ref,
+				Total:           total,
+				Parts:           make([]*SmppDeliverReqPkt, total),
+				profile:         pf,
+			},
+			payloads: make([][]byte, total),
+		}
+		pt.timer = time.AfterFunc(r.timeout, func() { r.expire(key, pt) })
+		r.pending[key] = pt
+	}
+
+	if pt.msg.Parts[seq-1] != nil { // 重复
+		return nil, nil
+	}
+	pt.msg.Parts[seq-1] = p
+	pt.payloads[seq-1] = payload
+	if seq == 1 {
+		pt.msg.UDH = h
+	}
+	pt.received++
+
+	if pt.received < int(total) {
+		return nil, nil
+	}
+
+	pt.timer.Stop()
+	delete(r.pending, key)
+	for _, b := range pt.payloads {
+		pt.msg.Content = append(pt.msg.Content, b...)
+	}
+	return pt.msg, nil
+}
+
+func (r *Reassembler) expire(key reassemblyKey, pt *partial) {
+	r.mu.Lock()
+	if r.pending[key] != pt {
+		r.mu.Unlock()
+		return
+	}
+	delete(r.pending, key)
+	r.mu.Unlock()
+
+	if r.onExpire != nil {
+		for _, b := range pt.payloads {
+			pt.msg.Content = append(pt.msg.Content, b...)
+		}
+		r.onExpire(pt.msg)
+	}
+}
+
+// 正在等待的长短信条数
+func (r *Reassembler) Pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.pending)
+}
+
+// 丢弃所有未收齐的长短信, 不触发超时回调
+func (r *Reassembler) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, pt := range r.pending {
+		pt.timer.Stop()
+		delete(r.pending, key)
+	}
+}
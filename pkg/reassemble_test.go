@@ -0,0 +1,196 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// 将 text 编码拆分后转为对端下发的 deliver_sm
+func deliverParts(t *testing.T, pf *Profile, dataCoding uint8, text string) []*SmppDeliverReqPkt {
+	t.Helper()
+	submits, err := pf.GetMsgPkgs(&SmppSubmitReqPkt{
+		SourceAddr:      "10086",
+		DestinationAddr: "8613800000000",
+		DataCoding:      dataCoding,
+		ShortMessage:    text,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := make([]*SmppDeliverReqPkt, 0, len(submits))
+	for _, s := range submits {
+		parts = append(parts, &SmppDeliverReqPkt{
+			SourceAddr:      s.SourceAddr,
+			DestinationAddr: s.DestinationAddr,
+			EsmClass:        s.EsmClass,
+			DataCoding:      s.DataCoding,
+			SmLength:        s.SmLength,
+			ShortMessage:    s.ShortMessage,
+			Options:         s.Options,
+		})
+	}
+	return parts
+}
+
+func TestReassemblerAdd(t *testing.T) {
+	long := strings.Repeat("0123456789", 40)
+	tests := []struct {
+		name       string
+		pf         *Profile
+		dataCoding uint8
+		text       string
+		order      []int // 加入的顺序, 可以重复
+	}{
+		{
+			name:  "single",
+			pf:    &Profile{},
+			text:  "hello",
+			order: []int{0},
+		},
+		{
+			name:  "udh in order",
+			pf:    &Profile{Segmenter: &Segmenter{Refs: fixedRef(7)}},
+			text:  long,
+			order: []int{0, 1, 2},
+		},
+		{
+			name:  "udh out of order with duplicate",
+			pf:    &Profile{Segmenter: &Segmenter{Refs: fixedRef(7)}},
+			text:  long,
+			order: []int{2, 0, 0, 1},
+		},
+		{
+			name:  "udh 16 bit ref packed",
+			pf:    &Profile{Packed7Bit: true, Segmenter: &Segmenter{Ref16: true, Refs: fixedRef(0x1234)}},
+			text:  long,
+			order: []int{1, 2, 0},
+		},
+		{
+			name:  "sar",
+			pf:    &Profile{Segmenter: &Segmenter{Mode: CONCAT_SAR, Refs: fixedRef(0x1234)}},
+			text:  long,
+			order: []int{1, 0, 2},
+		},
+		{
+			name:       "ucs2",
+			pf:         &Profile{Segmenter: &Segmenter{Refs: fixedRef(9)}},
+			dataCoding: UCS2,
+			text:       strings.Repeat("长短信", 30),
+			order:      []int{1, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := deliverParts(t, tt.pf, tt.dataCoding, tt.text)
+			r := NewReassembler(time.Minute, nil)
+			r.SetProfile(tt.pf)
+
+			var msg *LongMessage
+			for i, n := range tt.order {
+				m, err := r.Add(parts[n])
+				if err != nil {
+					t.Fatalf("Add() error = %v", err)
+				}
+				if i < len(tt.order)-1 && m != nil {
+					t.Fatalf("Add() completed after %d parts", i+1)
+				}
+				msg = m
+			}
+			if msg == nil {
+				t.Fatal("Add() did not complete")
+			}
+			if r.Pending() != 0 {
+				t.Errorf("Pending() = %d, want 0", r.Pending())
+			}
+			if int(msg.Total) != len(parts) {
+				t.Errorf("Total = %d, want %d", msg.Total, len(parts))
+			}
+			for i, p := range msg.Parts {
+				if p != parts[i] {
+					t.Errorf("Parts[%d] is not the part with seq %d", i, i+1)
+				}
+			}
+			text, err := msg.Text()
+			if err != nil || text != tt.text {
+				t.Errorf("Text() = %q, %v, want %q", text, err, tt.text)
+			}
+		})
+	}
+}
+
+func TestReassemblerInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		h    UDH
+	}{
+		{"zero total", UDH{NewConcatIE(1, 0, 1, false)}},
+		{"zero seq", UDH{NewConcatIE(1, 2, 0, false)}},
+		{"seq above total", UDH{NewConcatIE(1, 2, 3, false)}},
+	}
+
+	r := NewReassembler(time.Minute, nil)
+	for _, tt := range tests {
+		p := &SmppDeliverReqPkt{EsmClass: SM_UDH_GSM, ShortMessage: string(tt.h.Join([]byte("x")))}
+		if _, err := r.Add(p); err != ErrSegmentInvalid {
+			t.Errorf("%s: Add() error = %v, want %v", tt.name, err, ErrSegmentInvalid)
+		}
+	}
+
+	bad := &SmppDeliverReqPkt{EsmClass: SM_UDH_GSM, ShortMessage: string([]byte{0x05, 0x00})}
+	if _, err := r.Add(bad); err != ErrUDHLength {
+		t.Errorf("Add() error = %v, want %v", err, ErrUDHLength)
+	}
+}
+
+func TestReassemblerExpire(t *testing.T) {
+	pf := &Profile{Segmenter: &Segmenter{Refs: fixedRef(3)}}
+	parts := deliverParts(t, pf, ASCII, strings.Repeat("x", 400))
+
+	expired := make(chan *LongMessage, 1)
+	r := NewReassembler(20*time.Millisecond, func(m *LongMessage) { expired <- m })
+	r.SetProfile(pf)
+	if m, err := r.Add(parts[1]); m != nil || err != nil {
+		t.Fatalf("Add() = %v, %v", m, err)
+	}
+
+	select {
+	case m := <-expired:
+		if m.Parts[0] != nil || m.Parts[1] != parts[1] || m.Parts[2] != nil {
+			t.Errorf("expired Parts = %v", m.Parts)
+		}
+		if len(m.Content) != 153 {
+			t.Errorf("expired Content len = %d, want 153", len(m.Content))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("onExpire was not called")
+	}
+	if r.Pending() != 0 {
+		t.Errorf("Pending() = %d, want 0", r.Pending())
+	}
+}
+
+func TestReassemblerReset(t *testing.T) {
+	pf := &Profile{Segmenter: &Segmenter{Refs: fixedRef(3)}}
+	parts := deliverParts(t, pf, ASCII, strings.Repeat("x", 400))
+
+	called := make(chan struct{}, 1)
+	r := NewReassembler(20*time.Millisecond, func(*LongMessage) { called <- struct{}{} })
+	r.SetProfile(pf)
+	r.Add(parts[0])
+	if r.Pending() != 1 {
+		t.Fatalf("Pending() = %d, want 1", r.Pending())
+	}
+	r.Reset()
+	if r.Pending() != 0 {
+		t.Errorf("Pending() = %d, want 0", r.Pending())
+	}
+
+	select {
+	case <-called:
+		t.Error("onExpire called after Reset")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
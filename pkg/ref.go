@@ -0,0 +1,70 @@
+package pkg
+
+import (
+	"errors"
+	"hash/fnv"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// 长短信参考号分配器, 实现需要保证并发安全
+type RefAllocator interface {
+	// 为发往 dest 的长短信分配参考号, ref16 为 false 时参考号不超过 0xFF
+	NextRef(dest string, ref16 bool) uint16
+}
+
+var ErrRefStripe = errors.New("striped ref allocator: too many instances for 8 bit refs")
+
+// 按目的号码散列的计数器个数
+const refBuckets = 4096
+
+type refAllocator struct {
+	offset   uint32
+	stride   uint32
+	counters [refBuckets]uint32
+}
+
+// 按目的号码分别计数的参考号分配器, 计数器的初始值随机
+func NewRefAllocator() RefAllocator {
+	a, _ := NewStripedRefAllocator(0, 1)
+	return a
+}
+
+// 多个网关实例共同发送时使用的参考号分配器
+// 第 index 个实例 (共 count 个) 只分配对 count 取模等于 index 的参考号, 实例之间不会冲突
+// count 为 2 的幂时 8 位与 16 位参考号都能均匀划分
+// 每个实例至少需要 2 个 8 位参考号, count 大于 128 时返回 ErrRefStripe
+func NewStripedRefAllocator(index, count uint16) (RefAllocator, error) {
+	if count == 0 {
+		count = 1
+	}
+	if 0x100/uint32(count) < 2 {
+		return nil, ErrRefStripe
+	}
+
+	a := &refAllocator{
+		offset: uint32(index % count),
+		stride: uint32(count),
+	}
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for i := range a.counters {
+		a.counters[i] = r.Uint32()
+	}
+	return a, nil
+}
+
+func (a *refAllocator) NextRef(dest string, ref16 bool) uint16 {
+	h := fnv.New32a()
+	h.Write([]byte(dest))
+	n := atomic.AddUint32(&a.counters[h.Sum32()%refBuckets], 1)
+
+	space := uint32(0x100)
+	if ref16 {
+		space = 0x10000
+	}
+	slots := space / a.stride
+	return uint16(((n%slots)*a.stride + a.offset) % space)
+}
+
+var DefaultRefAllocator = NewRefAllocator()
@@ -0,0 +1,93 @@
+package pkg
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestNewStripedRefAllocator(t *testing.T) {
+	tests := []struct {
+		index, count uint16
+		wantErr      bool
+	}{
+		{0, 0, false},
+		{0, 1, false},
+		{3, 4, false},
+		{127, 128, false},
+		{0, 129, true},
+		{0, 256, true},
+	}
+	for _, tt := range tests {
+		_, err := NewStripedRefAllocator(tt.index, tt.count)
+		if (err == ErrRefStripe) != tt.wantErr {
+			t.Errorf("NewStripedRefAllocator(%d, %d) error = %v, wantErr %v", tt.index, tt.count, err, tt.wantErr)
+		}
+	}
+}
+
+func TestStripedRefAllocatorNextRef(t *testing.T) {
+	tests := []struct {
+		index, count uint16
+		ref16        bool
+	}{
+		{0, 1, false},
+		{1, 4, false},
+		{3, 4, true},
+		{127, 128, false},
+		{5, 100, true},
+	}
+
+	for _, tt := range tests {
+		a, err := NewStripedRefAllocator(tt.index, tt.count)
+		if err != nil {
+			t.Fatal(err)
+		}
+		limit := uint16(0xFF)
+		if tt.ref16 {
+			limit = 0xFFFF
+		}
+
+		seen := make(map[uint16]bool)
+		for i := 0; i < 300; i++ {
+			ref := a.NextRef("8613800000000", tt.ref16)
+			if ref > limit {
+				t.Errorf("index %d/%d: ref %#x exceeds %#x", tt.index, tt.count, ref, limit)
+			}
+			if ref%tt.count != tt.index {
+				t.Errorf("index %d/%d: ref %#x is outside its stripe", tt.index, tt.count, ref)
+			}
+			seen[ref] = true
+		}
+		if len(seen) < 2 {
+			t.Errorf("index %d/%d: only %d distinct refs", tt.index, tt.count, len(seen))
+		}
+	}
+}
+
+func TestRefAllocatorPerDestination(t *testing.T) {
+	a := NewRefAllocator()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		refs = make(map[uint16]int)
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 32; j++ {
+				ref := a.NextRef("8613800000000", false)
+				mu.Lock()
+				refs[ref]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 同一目的号码连续分配的 256 个 8 位参考号互不相同
+	if len(refs) != 256 {
+		t.Errorf("got %d distinct refs, want 256", len(refs))
+	}
+}
@@ -0,0 +1,242 @@
+package pkg
+
+import "errors"
+
+var ErrTooManySegments = errors.New("segment: message needs more than 255 segments")
+
+// 单条短信用户数据的最大字节数
+const MaxUserDataLen = 140
+
+// 长短信的拼接方式
+type ConcatMode uint8
+
+const (
+	CONCAT_UDH ConcatMode = iota // 用户数据头中的长短信信息单元
+	CONCAT_SAR                   // sar_msg_ref_num, sar_total_segments, sar_segment_seqnum 可选参数
+)
+
+// 长短信拆分后的一条短信
+type Segment struct {
+	Mode    ConcatMode
+	Ref     uint16 // 长短信参考号
+	Total   uint8  // 总条数
+	Seq     uint8  // 序号, 从 1 开始
+	UDH     UDH    // 用户数据头, 不拆分且没有其他信息单元时为 nil
+	Payload []byte // 用户数据
+}
+
+// 拼接用户数据头与用户数据, 作为 short_message 的内容
+func (s *Segment) ShortMessage() []byte {
+	return s.UDH.Join(s.Payload)
+}
+
+// SAR 方式拼接时需要携带的可选参数, 其他情况为 nil
+func (s *Segment) Options() Options {
+	if s.Mode != CONCAT_SAR || s.Total <= 1 {
+		return nil
+	}
+	return Options{
+		TAG_SarMsgRefNum:     NewTLV(TAG_SarMsgRefNum, packUi16(s.Ref)),
+		TAG_SarTotalSegments: NewTLV(TAG_SarTotalSegments, []byte{s.Total}),
+		TAG_SarSegmentSeqnum: NewTLV(TAG_SarSegmentSeqnum, []byte{s.Seq}),
+	}
+}
+
+// 长短信拆分器
+// GSM 7 bit 内容按 septet 计数且不会拆开转义序列, UCS2 内容按码元计数且不会拆开代理对
+// SAR 方式拼接时仍为 SMSC 下发时添加的用户数据头预留长度
+type Segmenter struct {
+	Mode  ConcatMode   // 拼接方式, 不同 SMSC 的要求可能不同
+	Ref16 bool         // 使用 16 位参考号
+	Refs  RefAllocator // 参考号分配器, 为 nil 时使用 DefaultRefAllocator
+}
+
+var DefaultSegmenter = &Segmenter{}
+
+// 用户数据的字符集
+type alphabet uint8
+
+const (
+	alphabetOctet alphabet = iota
+	alphabetGSM7
+	alphabetUCS2
+	alphabetGB18030
+	alphabetShiftJIS
+	alphabetEUCJP
+	alphabetEUCKR
+)
+
+func alphabetOf(msgFmt uint8) alphabet {
+	switch DCS(msgFmt).DataCoding() {
+	case ASCII:
+		return alphabetGSM7
+	case UCS2:
+		return alphabetUCS2
+	case GB18030:
+		return alphabetGB18030
+	case JIS, PICTOGRAM:
+		return alphabetShiftJIS
+	case EXTENDED_KANJI:
+		return alphabetEUCJP
+	case KSC5601:
+		return alphabetEUCKR
+	}
+	return alphabetOctet
+}
+
+// 用户数据头长度为 udhLen 时, 一条短信能容纳的内容字节数
+// GSM 7 bit 内容每个 septet 占一个字节, 由 SMSC 负责压缩
+func capacity(a alphabet, udhLen int) int {
+	octets := MaxUserDataLen - udhLen
+	switch a {
+	case alphabetGSM7:
+		return octets * 8 / 7
+	case alphabetUCS2:
+		return octets / 2 * 2
+	}
+	return octets
+}
+
+// 从 content[i] 开始的一个完整字符所占字节数
+func charLen(a alphabet, content []byte, i int) int {
+	n := 1
+	switch a {
+	case alphabetGSM7:
+		if content[i] == escapeSequence {
+			n = 2
+		}
+	case alphabetUCS2:
+		n = 2
+		if content[i] >= 0xD8 && content[i] <= 0xDB { // high surrogate
+			n = 4
+		}
+	case alphabetGB18030:
+		if content[i] >= 0x81 && content[i] <= 0xFE {
+			n = 2
+			if i+1 < len(content) && content[i+1] >= 0x30 && content[i+1] <= 0x39 {
+				n = 4
+			}
+		}
+	case alphabetShiftJIS:
+		if (content[i] >= 0x81 && content[i] <= 0x9F) || (content[i] >= 0xE0 && content[i] <= 0xFC) {
+			n = 2
+		}
+	case alphabetEUCJP:
+		switch {
+		case content[i] == 0x8F: // JIS X 0212
+			n = 3
+		case content[i] == 0x8E || (content[i] >= 0xA1 && content[i] <= 0xFE):
+			n = 2
+		}
+	case alphabetEUCKR:
+		if content[i] >= 0x81 && content[i] <= 0xFE {
+			n = 2
+		}
+	}
+
+	if i+n > len(content) {
+		n = len(content) - i
+	}
+	return n
+}
+
+// 占满 size 个 septet 且以 CR 结尾的 GSM 7 bit 内容压缩后恰好占满最后一个字节,
+// 按 Pack7Bit 需要再补一个 CR 以免被当作填充, 会超出一条短信的长度
+func overflowsCR(a alphabet, content []byte, size int) bool {
+	return a == alphabetGSM7 && len(content) == size && size > 0 && content[size-1] == '\r'
+}
+
+// 按字符边界将 content 切分为每段不超过 size 字节
+// GSM 7 bit 内容占满一段且以 CR 结尾时, 将最后一个字符移到下一段
+func chunk(a alphabet, content []byte, size int) [][]byte {
+	var chunks [][]byte
+	start, last := 0, 0 // last 为上一个字符的起始位置
+	for i := 0; i < len(content); {
+		n := charLen(a, content, i)
+		if i+n-start > size {
+			end := i
+			if overflowsCR(a, content[start:i], size) && last > start {
+				end = last
+			}
+			chunks = append(chunks, content[start:end])
+			start = end
+		}
+		last = i
+		i += n
+	}
+	if overflowsCR(a, content[start:], size) && last > start {
+		chunks = append(chunks, content[start:last])
+		start = last
+	}
+	return append(chunks, content[start:])
+}
+
+func (s *Segmenter) concatLen() int {
+	if s.Ref16 {
+		return NewConcatIE(0, 0, 0, true).Len()
+	}
+	return NewConcatIE(0, 0, 0, false).Len()
+}
+
+// 每条短信能容纳的内容字节数, multi 为 true 时为拆分后每条的容量
+func (s *Segmenter) capacity(msgFmt uint8, h UDH, multi bool) int {
+	a := alphabetOf(msgFmt)
+	if !multi {
+		return capacity(a, h.Len())
+	}
+
+	udhLen := h.Len() + s.concatLen()
+	if len(h) == 0 {
+		udhLen++ // UDHL
+	}
+	return capacity(a, udhLen)
+}
+
+func (s *Segmenter) chunks(msgFmt uint8, h UDH, content []byte) [][]byte {
+	if single := s.capacity(msgFmt, h, false); len(content) <= single && !overflowsCR(alphabetOf(msgFmt), content, single) {
+		return [][]byte{content}
+	}
+	return chunk(alphabetOf(msgFmt), content, s.capacity(msgFmt, h, true))
+}
+
+// 编码后的内容 content 需要拆分成的条数, h 为每条短信都需要携带的其他信息单元
+func (s *Segmenter) Count(msgFmt uint8, h UDH, content []byte) int {
+	return len(s.chunks(msgFmt, h, content))
+}
+
+// 将发往 dest 的编码后的内容 content 拆分为多条短信, h 为每条短信都需要携带的其他信息单元
+func (s *Segmenter) Split(dest string, msgFmt uint8, h UDH, content []byte) ([]*Segment, error) {
+	chunks := s.chunks(msgFmt, h, content)
+	if len(chunks) == 1 {
+		return []*Segment{{Mode: s.Mode, Total: 1, Seq: 1, UDH: h, Payload: chunks[0]}}, nil
+	}
+	if len(chunks) > 0xFF {
+		return nil, ErrTooManySegments
+	}
+
+	refs := s.Refs
+	if refs == nil {
+		refs = DefaultRefAllocator
+	}
+	ref16 := s.Ref16 || s.Mode == CONCAT_SAR // sar_msg_ref_num 为 16 位
+	ref := refs.NextRef(dest, ref16)
+
+	segments := make([]*Segment, 0, len(chunks))
+	for i, c := range chunks {
+		udh := h
+		if s.Mode == CONCAT_UDH {
+			udh = make(UDH, 0, len(h)+1)
+			udh = append(udh, NewConcatIE(ref, uint8(len(chunks)), uint8(i+1), s.Ref16))
+			udh = append(udh, h...)
+		}
+		segments = append(segments, &Segment{
+			Mode:    s.Mode,
+			Ref:     ref,
+			Total:   uint8(len(chunks)),
+			Seq:     uint8(i + 1),
+			UDH:     udh,
+			Payload: c,
+		})
+	}
+	return segments, nil
+}
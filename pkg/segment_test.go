@@ -0,0 +1,205 @@
+package pkg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type fixedRef uint16
+
+func (r fixedRef) NextRef(dest string, ref16 bool) uint16 {
+	return uint16(r)
+}
+
+func ucs2(t *testing.T, text string) []byte {
+	t.Helper()
+	b, err := EncodeContent(UCS2, text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestSegmenterSplit(t *testing.T) {
+	tests := []struct {
+		name    string
+		sg      *Segmenter
+		msgFmt  uint8
+		h       UDH
+		content []byte
+		lens    []int // 每条用户数据的字节数
+	}{
+		{
+			name:    "gsm7 single",
+			sg:      &Segmenter{},
+			msgFmt:  ASCII,
+			content: bytes.Repeat([]byte{'a'}, 160),
+			lens:    []int{160},
+		},
+		{
+			name:    "gsm7 two segments",
+			sg:      &Segmenter{},
+			msgFmt:  ASCII,
+			content: bytes.Repeat([]byte{'a'}, 161),
+			lens:    []int{153, 8},
+		},
+		{
+			name:    "gsm7 16 bit ref",
+			sg:      &Segmenter{Ref16: true},
+			msgFmt:  ASCII,
+			content: bytes.Repeat([]byte{'a'}, 161),
+			lens:    []int{152, 9},
+		},
+		{
+			name:    "gsm7 escape is not split",
+			sg:      &Segmenter{},
+			msgFmt:  ASCII,
+			content: append(bytes.Repeat([]byte{'a'}, 152), 0x1B, 0x65, 'b', 'c', 'd', 'e', 'f', 'g', 'h'),
+			lens:    []int{152, 9},
+		},
+		{
+			name:    "gsm7 full single ending in CR",
+			sg:      &Segmenter{},
+			msgFmt:  ASCII,
+			content: append(bytes.Repeat([]byte{'a'}, 159), '\r'),
+			lens:    []int{153, 7},
+		},
+		{
+			name:    "gsm7 full segment ending in CR",
+			sg:      &Segmenter{},
+			msgFmt:  ASCII,
+			content: append(append(bytes.Repeat([]byte{'a'}, 152), '\r'), bytes.Repeat([]byte{'b'}, 10)...),
+			lens:    []int{152, 11},
+		},
+		{
+			name:    "gsm7 full last segment ending in CR",
+			sg:      &Segmenter{},
+			msgFmt:  ASCII,
+			content: append(bytes.Repeat([]byte{'a'}, 305), '\r'),
+			lens:    []int{153, 152, 1},
+		},
+		{
+			name:    "gsm7 escape ending in CR",
+			sg:      &Segmenter{},
+			msgFmt:  ASCII,
+			content: append(append(bytes.Repeat([]byte{'a'}, 151), 0x1B, '\r'), bytes.Repeat([]byte{'b'}, 10)...),
+			lens:    []int{151, 12},
+		},
+		{
+			name:    "ucs2 single",
+			sg:      &Segmenter{},
+			msgFmt:  UCS2,
+			content: ucs2(t, strings.Repeat("你", 70)),
+			lens:    []int{140},
+		},
+		{
+			name:    "ucs2 two segments",
+			sg:      &Segmenter{},
+			msgFmt:  UCS2,
+			content: ucs2(t, strings.Repeat("你", 71)),
+			lens:    []int{134, 8},
+		},
+		{
+			name:    "ucs2 surrogate pair is not split",
+			sg:      &Segmenter{},
+			msgFmt:  UCS2,
+			content: ucs2(t, strings.Repeat("你", 66)+"😀"+"你你你"),
+			lens:    []int{132, 10},
+		},
+		{
+			name:    "octet with port",
+			sg:      &Segmenter{},
+			msgFmt:  BINARY,
+			h:       UDH{NewPortIE(2948, 9200, true)},
+			content: make([]byte, 140),
+			lens:    []int{128, 12},
+		},
+		{
+			name:    "sar keeps udh room",
+			sg:      &Segmenter{Mode: CONCAT_SAR},
+			msgFmt:  ASCII,
+			content: bytes.Repeat([]byte{'a'}, 161),
+			lens:    []int{153, 8},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.sg.Refs = fixedRef(0x1234)
+			segments, err := tt.sg.Split("8613800000000", tt.msgFmt, tt.h, tt.content)
+			if err != nil {
+				t.Fatalf("Split() error = %v", err)
+			}
+			if n := tt.sg.Count(tt.msgFmt, tt.h, tt.content); n != len(tt.lens) {
+				t.Errorf("Count() = %d, want %d", n, len(tt.lens))
+			}
+			if len(segments) != len(tt.lens) {
+				t.Fatalf("Split() got %d segments, want %d", len(segments), len(tt.lens))
+			}
+
+			var joined []byte
+			for i, s := range segments {
+				if len(s.Payload) != tt.lens[i] {
+					t.Errorf("segment %d len = %d, want %d", i+1, len(s.Payload), tt.lens[i])
+				}
+				if s.Seq != uint8(i+1) || s.Total != uint8(len(tt.lens)) {
+					t.Errorf("segment %d seq/total = %d/%d", i+1, s.Seq, s.Total)
+				}
+				if len(s.ShortMessage()) > MaxUserDataLen && tt.msgFmt != ASCII {
+					t.Errorf("segment %d short_message is %d bytes", i+1, len(s.ShortMessage()))
+				}
+				joined = append(joined, s.Payload...)
+			}
+			if !bytes.Equal(joined, tt.content) {
+				t.Errorf("joined payload differs from content")
+			}
+		})
+	}
+}
+
+func TestSegmenterConcat(t *testing.T) {
+	content := bytes.Repeat([]byte{'a'}, 200)
+
+	udh := &Segmenter{Refs: fixedRef(0x1234)}
+	segments, err := udh.Split("1", ASCII, UDH{NewPortIE(1, 2, false)}, content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range segments {
+		ref, total, seq, ok := s.UDH.Concat()
+		if !ok || ref != 0x34 || total != 2 || seq != s.Seq {
+			t.Errorf("Concat() = %#x, %d, %d, %v", ref, total, seq, ok)
+		}
+		if _, _, ok := s.UDH.Ports(); !ok {
+			t.Errorf("segment %d lost the port element", s.Seq)
+		}
+		if s.Options() != nil {
+			t.Errorf("segment %d has SAR options in UDH mode", s.Seq)
+		}
+	}
+
+	sar := &Segmenter{Mode: CONCAT_SAR, Refs: fixedRef(0x1234)}
+	segments, err = sar.Split("1", ASCII, nil, content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range segments {
+		if s.UDH != nil {
+			t.Errorf("segment %d has UDH in SAR mode", s.Seq)
+		}
+		opts := s.Options()
+		if !bytes.Equal(opts[TAG_SarMsgRefNum].Value, []byte{0x12, 0x34}) ||
+			!bytes.Equal(opts[TAG_SarTotalSegments].Value, []byte{2}) ||
+			!bytes.Equal(opts[TAG_SarSegmentSeqnum].Value, []byte{s.Seq}) {
+			t.Errorf("segment %d options = %v", s.Seq, opts)
+		}
+	}
+}
+
+func TestSegmenterTooManySegments(t *testing.T) {
+	sg := &Segmenter{Refs: fixedRef(1)}
+	if _, err := sg.Split("1", BINARY, nil, make([]byte, 134*256)); err != ErrTooManySegments {
+		t.Errorf("Split() error = %v, want %v", err, ErrTooManySegments)
+	}
+}
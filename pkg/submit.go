@@ -2,7 +2,6 @@ package pkg
 
 import (
 	"bytes"
-	"encoding/binary"
 	"errors"
 	"fmt"
 )
@@ -88,26 +87,26 @@ func (p *SmppSubmitReqPkt) Unpack(data []byte) error {
 
 	serviceType := r.ReadOCString(6)
 	p.ServiceType = string(serviceType)
-	p.SourceAddrTON = r.ReadByte()
-	p.SourceAddrNPI = r.ReadByte()
+	p.SourceAddrTON = r.ReadUint8()
+	p.SourceAddrNPI = r.ReadUint8()
 	sourceAddr := r.ReadOCString(21)
 	p.SourceAddr = string(sourceAddr)
-	p.DestAddrTON = r.ReadByte()
-	p.DestAddrNPI = r.ReadByte()
+	p.DestAddrTON = r.ReadUint8()
+	p.DestAddrNPI = r.ReadUint8()
 	destinationAddr := r.ReadOCString(21)
 	p.DestinationAddr = string(destinationAddr)
-	p.EsmClass = r.ReadByte()
-	p.ProtocolID = r.ReadByte()
-	p.PriorityFlag = r.ReadByte()
+	p.EsmClass = r.ReadUint8()
+	p.ProtocolID = r.ReadUint8()
+	p.PriorityFlag = r.ReadUint8()
 	scheduleDeliveryTime := r.ReadOCString(17)
 	p.ScheduleDeliveryTime = string(scheduleDeliveryTime)
 	validityPeriod := r.ReadOCString(17)
 	p.ValidityPeriod = string(validityPeriod)
-	p.RegisteredDelivery = r.ReadByte()
-	p.ReplaceIfPresentFlag = r.ReadByte()
-	p.DataCoding = r.ReadByte()
-	p.SmDefaultMsgID = r.ReadByte()
-	p.SmLength = r.ReadByte()
+	p.RegisteredDelivery = r.ReadUint8()
+	p.ReplaceIfPresentFlag = r.ReadUint8()
+	p.DataCoding = r.ReadUint8()
+	p.SmDefaultMsgID = r.ReadUint8()
+	p.SmLength = r.ReadUint8()
 	msgContent := make([]byte, p.SmLength)
 	r.ReadBytes(msgContent)
 	p.ShortMessage = string(msgContent)
@@ -148,6 +147,22 @@ func (p *SmppSubmitReqPkt) String() string {
 	return b.String()
 }
 
+// 解析短消息内容中的用户数据头, 未设置 UDHI 时用户数据头为 nil
+func (p *SmppSubmitReqPkt) UDH() (UDH, []byte, error) {
+	return splitUDH(p.EsmClass, p.ShortMessage)
+}
+
+// 设置用户数据头与用户数据, 并同步 esm_class 的 UDHI 标识
+func (p *SmppSubmitReqPkt) SetUDH(h UDH, payload []byte) {
+	if len(h) == 0 {
+		p.EsmClass &^= SM_UDH_GSM
+	} else {
+		p.EsmClass |= SM_UDH_GSM
+	}
+	p.ShortMessage = string(h.Join(payload))
+	p.SmLength = uint8(len(p.ShortMessage))
+}
+
 type SmppSubmitContentHeaderReqPkg struct {
 	LastProtocolLen uint8
 	UniqueIdLen     uint8
@@ -159,35 +174,32 @@ type SmppSubmitContentHeaderReqPkg struct {
 
 func GetSubmitMsgHeader(msgContent []byte) (*SmppSubmitContentHeaderReqPkg, error) {
 	header := &SmppSubmitContentHeaderReqPkg{}
-	r := newPkgReader(msgContent)
-	r.ReadInt(binary.BigEndian, &header.LastProtocolLen)
-	r.ReadInt(binary.BigEndian, &header.UniqueIdLen)
-	r.ReadInt(binary.BigEndian, &header.LastLen)
+	udh, _, err := ParseUDH(msgContent)
+	if err != nil {
+		return header, err
+	}
 
 	// 获取长短信header信息
-	if header.LastProtocolLen == 5 { // 6位协议头
-		if header.LastLen == 3 && header.UniqueIdLen == 0 {
-			uniqueId := make([]byte, 1)
-			r.ReadBytes(uniqueId)
-			header.UniqueId = uint16(uniqueId[0])
-			r.ReadInt(binary.BigEndian, &header.PkTotal)
-			r.ReadInt(binary.BigEndian, &header.PkNumber)
-		}
-	} else if header.LastProtocolLen == 6 { // 7位协议头
-		if header.LastLen == 4 && header.UniqueIdLen == 8 {
-			r.ReadInt(binary.BigEndian, &header.UniqueId)
-			r.ReadInt(binary.BigEndian, &header.PkTotal)
-			r.ReadInt(binary.BigEndian, &header.PkNumber)
-		}
-	} else {
+	ref, total, seq, ok := udh.Concat()
+	if !ok {
 		return header, errors.New("msg header len illegal")
 	}
+	ie := udh.Get(IEI_CONCAT_8)
+	if ie == nil {
+		ie = udh.Get(IEI_CONCAT_16)
+	}
+	header.LastProtocolLen = msgContent[0]
+	header.UniqueIdLen = uint8(ie.ID)
+	header.LastLen = uint8(len(ie.Data))
+	header.UniqueId = ref
+	header.PkTotal = total
+	header.PkNumber = seq
 
 	if header.PkNumber == 0 || header.PkNumber > header.PkTotal {
 		return header, errors.New("msg header len illegal")
 	}
 
-	return header, r.Error()
+	return header, nil
 }
 
 type SmppSubmitRespPkt struct {
@@ -0,0 +1,106 @@
+package pkg
+
+import "strings"
+
+// 一处替换
+type Substitution struct {
+	Offset int    // 在原文中的字节偏移
+	From   rune   // 原字符
+	To     string // 替换后的内容, 可以为空
+}
+
+// 常见的不在 GSM 7 bit 默认字母表中的字符及其替换
+var DefaultTransliterations = map[rune]string{
+	// 引号
+	'‘': "'", '’': "'", '‚': "'", '‛': "'", '′': "'", '`': "'", '´': "'",
+	'“': "\"", '”': "\"", '„': "\"", '‟': "\"", '″': "\"", '«': "\"", '»': "\"",
+	// 连字符与破折号
+	'‐': "-", '‑': "-", '‒': "-", '–': "-", '—': "-", '―': "-", '−': "-",
+	// 空白
+	'\t': " ", '\u00A0': " ", '\u2000': " ", '\u2001': " ", '\u2002': " ", '\u2003': " ",
+	'\u2004': " ", '\u2005': " ", '\u2006': " ", '\u2007': " ", '\u2008': " ", '\u2009': " ",
+	'\u200A': " ", '\u202F': " ", '\u205F': " ", '\u3000': " ",
+	'\u200B': "", '\u200C': "", '\u200D': "", '\u2060': "", '\uFEFF': "",
+	// 其他符号
+	'…': "...", '•': "-", '·': ".", '™': "TM", '©': "(C)", '®': "(R)",
+	// 带重音的字母
+	'á': "a", 'â': "a", 'ã': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'Á': "A", 'À': "A", 'Â': "A", 'Ã': "A", 'Ā': "A", 'Ă': "A", 'Ą': "A",
+	'ç': "Ç", 'ć': "c", 'č': "c", 'Ć': "C", 'Č': "C",
+	'ď': "d", 'Ď': "D", 'đ': "d", 'Đ': "D",
+	'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'È': "E", 'Ê': "E", 'Ë': "E", 'Ē': "E", 'Ė': "E", 'Ę': "E", 'Ě': "E",
+	'ğ': "g", 'Ğ': "G",
+	'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ı': "i",
+	'Í': "I", 'Ì': "I", 'Î': "I", 'Ï': "I", 'Ī': "I", 'İ': "I",
+	'ł': "l", 'Ł': "L", 'ľ': "l", 'Ľ': "L",
+	'ń': "n", 'ň': "n", 'Ń': "N", 'Ň': "N",
+	'ó': "o", 'ô': "o", 'õ': "o", 'ō': "o", 'ő': "ö",
+	'Ó': "O", 'Ò': "O", 'Ô': "O", 'Õ': "O", 'Ō': "O", 'Ő': "Ö",
+	'œ': "oe", 'Œ': "OE",
+	'ř': "r", 'Ř': "R",
+	'ś': "s", 'š': "s", 'ş': "s", 'Ś': "S", 'Š': "S", 'Ş': "S",
+	'ť': "t", 'Ť': "T", 'ţ': "t", 'Ţ': "T",
+	'ú': "u", 'û': "u", 'ū': "u", 'ů': "u", 'ű': "ü",
+	'Ú': "U", 'Ù': "U", 'Û': "U", 'Ū': "U", 'Ů': "U", 'Ű': "Ü",
+	'ý': "y", 'ÿ': "y", 'Ý': "Y", 'Ÿ': "Y",
+	'ź': "z", 'ż': "z", 'ž': "z", 'Ź': "Z", 'Ż': "Z", 'Ž': "Z",
+}
+
+// 将不在 GSM 7 bit 默认字母表中的字符按替换表替换
+type Transliterator struct {
+	table map[rune]string
+}
+
+// 合并多个替换表, 后面的表覆盖前面的同一字符; 不传时使用 DefaultTransliterations
+// 替换后的内容应只包含 GSM 7 bit 默认字母表中的字符, 否则替换不会生效
+func NewTransliterator(tables ...map[rune]string) *Transliterator {
+	if len(tables) == 0 {
+		tables = []map[rune]string{DefaultTransliterations}
+	}
+	t := &Transliterator{table: make(map[rune]string)}
+	for _, table := range tables {
+		for r, s := range table {
+			if len(ValidateGSM7String(s)) == 0 {
+				t.table[r] = s
+			}
+		}
+	}
+	return t
+}
+
+var DefaultTransliterator = NewTransliterator()
+
+func isGSM7(r rune) bool {
+	if _, ok := forwardLookup[r]; ok {
+		return true
+	}
+	_, ok := forwardEscape[r]
+	return ok
+}
+
+// 替换 text 中不在 GSM 7 bit 默认字母表中且在替换表中的字符, 返回替换后的文本与替换记录
+// 替换表中没有的字符保持不变
+func (t *Transliterator) Transliterate(text string) (string, []Substitution) {
+	var (
+		b    strings.Builder
+		subs []Substitution
+	)
+	for i, r := range text {
+		if isGSM7(r) {
+			b.WriteRune(r)
+			continue
+		}
+		s, ok := t.table[r]
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+		b.WriteString(s)
+		subs = append(subs, Substitution{Offset: i, From: r, To: s})
+	}
+	if len(subs) == 0 {
+		return text, nil
+	}
+	return b.String(), subs
+}
@@ -0,0 +1,66 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectEncodingTransliterate(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		dataCoding uint8
+		subs       int
+	}{
+		// 替换后条数更少时才替换
+		{"cheaper after substitution", strings.Repeat("a", 100) + "“quoted”", ASCII, 2},
+		// 条数相同时保留原文
+		{"same segments keeps original", "“hi”", UCS2, 0},
+		// 替换后仍需 UCS2 时保留原文
+		{"still needs ucs2", strings.Repeat("a", 100) + "“你”", UCS2, 0},
+	}
+
+	pf := &Profile{NoLanguageShift: true, Transliterator: DefaultTransliterator}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := pf.SelectEncoding(tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.DataCoding != tt.dataCoding || len(e.Substitutions) != tt.subs {
+				t.Errorf("SelectEncoding() = data_coding %d with %d substitutions, want %d with %d",
+					e.DataCoding, len(e.Substitutions), tt.dataCoding, tt.subs)
+			}
+		})
+	}
+}
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+		subs []Substitution
+	}{
+		{"plain", "plain", nil},
+		{"“a”", "\"a\"", []Substitution{{0, '“', "\""}, {4, '”', "\""}}},
+		{"x…", "x...", []Substitution{{1, '…', "..."}}},
+		{"a​b", "ab", []Substitution{{1, '​', ""}}},
+		{"你", "你", nil},
+	}
+
+	for _, tt := range tests {
+		got, subs := DefaultTransliterator.Transliterate(tt.text)
+		if got != tt.want {
+			t.Errorf("Transliterate(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+		if len(subs) != len(tt.subs) {
+			t.Errorf("Transliterate(%q) substitutions = %v, want %v", tt.text, subs, tt.subs)
+			continue
+		}
+		for i := range subs {
+			if subs[i] != tt.subs[i] {
+				t.Errorf("Transliterate(%q) substitution %d = %v, want %v", tt.text, i, subs[i], tt.subs[i])
+			}
+		}
+	}
+}
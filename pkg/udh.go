@@ -0,0 +1,241 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var ErrUDHLength = errors.New("UDH: error length")
+
+// 信息单元标识 Information Element Identifier, 见 3GPP TS 23.040 9.2.3.24
+type IEI uint8
+
+const (
+	IEI_CONCAT_8               IEI = 0x00 // 长短信, 8 位参考号
+	IEI_SPECIAL_SMS            IEI = 0x01 // 特殊短信指示
+	IEI_PORT_8                 IEI = 0x04 // 应用端口, 8 位地址
+	IEI_PORT_16                IEI = 0x05 // 应用端口, 16 位地址
+	IEI_CONCAT_16              IEI = 0x08 // 长短信, 16 位参考号
+	IEI_NATIONAL_SINGLE_SHIFT  IEI = 0x24 // 国家语言单次转义表
+	IEI_NATIONAL_LOCKING_SHIFT IEI = 0x25 // 国家语言锁定转义表
+)
+
+// 信息单元
+type InformationElement struct {
+	ID   IEI
+	Data []byte
+}
+
+func (ie *InformationElement) Len() int {
+	return 2 + len(ie.Data)
+}
+
+func (ie *InformationElement) String() string {
+	return fmt.Sprintf("IEI: 0x%02x Data: %x", uint8(ie.ID), ie.Data)
+}
+
+// 长短信信息单元, ref16 为 true 时使用 16 位参考号
+func NewConcatIE(ref uint16, total, seq uint8, ref16 bool) *InformationElement {
+	if ref16 {
+		return &InformationElement{ID: IEI_CONCAT_16, Data: []byte{byte(ref >> 8), byte(ref), total, seq}}
+	}
+	return &InformationElement{ID: IEI_CONCAT_8, Data: []byte{byte(ref), total, seq}}
+}
+
+// 应用端口信息单元, port16 为 false 时端口号只取低 8 位
+func NewPortIE(dst, src uint16, port16 bool) *InformationElement {
+	if port16 {
+		data := make([]byte, 4)
+		binary.BigEndian.PutUint16(data, dst)
+		binary.BigEndian.PutUint16(data[2:], src)
+		return &InformationElement{ID: IEI_PORT_16, Data: data}
+	}
+	return &InformationElement{ID: IEI_PORT_8, Data: []byte{byte(dst), byte(src)}}
+}
+
+// 特殊短信指示信息单元, typ 取 bit0-6, store 对应 bit7
+func NewSpecialSMSIE(store bool, typ, count uint8) *InformationElement {
+	b := typ & 0x7F
+	if store {
+		b |= 0x80
+	}
+	return &InformationElement{ID: IEI_SPECIAL_SMS, Data: []byte{b, count}}
+}
+
+// 国家语言单次转义表信息单元
+func NewSingleShiftIE(lang uint8) *InformationElement {
+	return &InformationElement{ID: IEI_NATIONAL_SINGLE_SHIFT, Data: []byte{lang}}
+}
+
+// 国家语言锁定转义表信息单元
+func NewLockingShiftIE(lang uint8) *InformationElement {
+	return &InformationElement{ID: IEI_NATIONAL_LOCKING_SHIFT, Data: []byte{lang}}
+}
+
+// 用户数据头 User Data Header, 由若干信息单元组成
+type UDH []*InformationElement
+
+// 解析 ud 开头的用户数据头, 返回用户数据头以及剩余的用户数据
+func ParseUDH(ud []byte) (UDH, []byte, error) {
+	if len(ud) == 0 {
+		return nil, ud, ErrUDHLength
+	}
+
+	end := 1 + int(ud[0])
+	if end > len(ud) {
+		return nil, ud, ErrUDHLength
+	}
+
+	var (
+		h UDH
+		p = 1
+	)
+	for p < end {
+		if end-p < 2 { // less than IEI len + Length len
+			return nil, ud, ErrUDHLength
+		}
+
+		id := IEI(ud[p])
+		l := int(ud[p+1])
+		p += 2
+
+		if end-p < l { // remaining not enough
+			return nil, ud, ErrUDHLength
+		}
+
+		data := make([]byte, l)
+		copy(data, ud[p:p+l])
+		h = append(h, &InformationElement{ID: id, Data: data})
+		p += l
+	}
+
+	return h, ud[end:], nil
+}
+
+// 用户数据头的总长度, 包含 UDHL 本身; 没有信息单元时为 0
+func (h UDH) Len() int {
+	if len(h) == 0 {
+		return 0
+	}
+
+	length := 1
+	for _, ie := range h {
+		length += ie.Len()
+	}
+	return length
+}
+
+// 序列化为字节流, 包含 UDHL
+func (h UDH) Bytes() []byte {
+	if len(h) == 0 {
+		return nil
+	}
+
+	b := make([]byte, 1, h.Len())
+	b[0] = byte(h.Len() - 1)
+	for _, ie := range h {
+		b = append(b, byte(ie.ID), byte(len(ie.Data)))
+		b = append(b, ie.Data...)
+	}
+	return b
+}
+
+// 将用户数据头拼接到 payload 之前
+func (h UDH) Join(payload []byte) []byte {
+	b := h.Bytes()
+	return append(b, payload...)
+}
+
+// 返回第一个标识为 id 的信息单元
+func (h UDH) Get(id IEI) *InformationElement {
+	for _, ie := range h {
+		if ie.ID == id {
+			return ie
+		}
+	}
+	return nil
+}
+
+// 长短信参考号、总条数以及序号
+func (h UDH) Concat() (ref uint16, total, seq uint8, ok bool) {
+	for _, ie := range h {
+		switch {
+		case ie.ID == IEI_CONCAT_8 && len(ie.Data) == 3:
+			return uint16(ie.Data[0]), ie.Data[1], ie.Data[2], true
+		case ie.ID == IEI_CONCAT_16 && len(ie.Data) == 4:
+			return binary.BigEndian.Uint16(ie.Data), ie.Data[2], ie.Data[3], true
+		}
+	}
+	return 0, 0, 0, false
+}
+
+// 目的端口与源端口
+func (h UDH) Ports() (dst, src uint16, ok bool) {
+	for _, ie := range h {
+		switch {
+		case ie.ID == IEI_PORT_8 && len(ie.Data) == 2:
+			return uint16(ie.Data[0]), uint16(ie.Data[1]), true
+		case ie.ID == IEI_PORT_16 && len(ie.Data) == 4:
+			return binary.BigEndian.Uint16(ie.Data), binary.BigEndian.Uint16(ie.Data[2:]), true
+		}
+	}
+	return 0, 0, false
+}
+
+// 国家语言单次转义表与锁定转义表, 未设置时为 0 (GSM 7 bit 默认字母表)
+func (h UDH) NationalLanguage() (single, locking uint8) {
+	for _, ie := range h {
+		if len(ie.Data) != 1 {
+			continue
+		}
+		switch ie.ID {
+		case IEI_NATIONAL_SINGLE_SHIFT:
+			single = ie.Data[0]
+		case IEI_NATIONAL_LOCKING_SHIFT:
+			locking = ie.Data[0]
+		}
+	}
+	return
+}
+
+// 特殊短信指示
+type SpecialSMSIndication struct {
+	Store bool  // 处理后是否保存该短信
+	Type  uint8 // 指示类型, bit0-1 为基本类型, bit2-4 为扩展类型, bit5-6 为 profile id
+	Count uint8 // 等待的消息数
+}
+
+// 所有特殊短信指示
+func (h UDH) SpecialSMS() []SpecialSMSIndication {
+	var s []SpecialSMSIndication
+	for _, ie := range h {
+		if ie.ID != IEI_SPECIAL_SMS || len(ie.Data) != 2 {
+			continue
+		}
+		s = append(s, SpecialSMSIndication{
+			Store: ie.Data[0]&0x80 != 0,
+			Type:  ie.Data[0] & 0x7F,
+			Count: ie.Data[1],
+		})
+	}
+	return s
+}
+
+func (h UDH) String() string {
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "--- UDH ---")
+	for _, ie := range h {
+		fmt.Fprintln(&b, ie.String())
+	}
+	return b.String()
+}
+
+// 按 esm_class 的 UDHI 标识拆分短消息内容为用户数据头和用户数据
+func splitUDH(esmClass uint8, shortMessage string) (UDH, []byte, error) {
+	if esmClass&SM_UDH_GSM == 0 {
+		return nil, []byte(shortMessage), nil
+	}
+	return ParseUDH([]byte(shortMessage))
+}
@@ -0,0 +1,175 @@
+package pkg
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseUDH(t *testing.T) {
+	tests := []struct {
+		name    string
+		ud      []byte
+		ids     []IEI
+		rest    []byte
+		wantErr bool
+	}{
+		{
+			name: "concat 8",
+			ud:   []byte{0x05, 0x00, 0x03, 0x2A, 0x02, 0x01, 'h', 'i'},
+			ids:  []IEI{IEI_CONCAT_8},
+			rest: []byte("hi"),
+		},
+		{
+			name: "concat 16 and port 16",
+			ud:   []byte{0x0C, 0x08, 0x04, 0x01, 0x02, 0x03, 0x01, 0x05, 0x04, 0x0B, 0x84, 0x23, 0xF0, 0xAA},
+			ids:  []IEI{IEI_CONCAT_16, IEI_PORT_16},
+			rest: []byte{0xAA},
+		},
+		{
+			name: "empty header",
+			ud:   []byte{0x00, 'x'},
+			rest: []byte("x"),
+		},
+		{
+			name:    "no data",
+			ud:      []byte{},
+			wantErr: true,
+		},
+		{
+			name:    "udhl longer than user data",
+			ud:      []byte{0x05, 0x00, 0x03},
+			wantErr: true,
+		},
+		{
+			name:    "truncated element",
+			ud:      []byte{0x04, 0x00, 0x03, 0x01, 0x02},
+			wantErr: true,
+		},
+		{
+			name:    "missing element length",
+			ud:      []byte{0x01, 0x00},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, rest, err := ParseUDH(tt.ud)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseUDH() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(h) != len(tt.ids) {
+				t.Fatalf("ParseUDH() got %d elements, want %d", len(h), len(tt.ids))
+			}
+			for i, id := range tt.ids {
+				if h[i].ID != id {
+					t.Errorf("element %d id = 0x%02x, want 0x%02x", i, h[i].ID, id)
+				}
+			}
+			if !bytes.Equal(rest, tt.rest) {
+				t.Errorf("ParseUDH() rest = %x, want %x", rest, tt.rest)
+			}
+		})
+	}
+}
+
+func TestUDHRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		h    UDH
+		want []byte
+	}{
+		{
+			name: "nil",
+			h:    nil,
+			want: nil,
+		},
+		{
+			name: "concat 8",
+			h:    UDH{NewConcatIE(0x12A, 3, 2, false)},
+			want: []byte{0x05, 0x00, 0x03, 0x2A, 0x03, 0x02},
+		},
+		{
+			name: "concat 16",
+			h:    UDH{NewConcatIE(0x0102, 3, 1, true)},
+			want: []byte{0x06, 0x08, 0x04, 0x01, 0x02, 0x03, 0x01},
+		},
+		{
+			name: "port 8",
+			h:    UDH{NewPortIE(0x1234, 0x56, false)},
+			want: []byte{0x04, 0x04, 0x02, 0x34, 0x56},
+		},
+		{
+			name: "port 16 and special sms",
+			h:    UDH{NewPortIE(2948, 9200, true), NewSpecialSMSIE(true, 0x01, 3)},
+			want: []byte{0x0A, 0x05, 0x04, 0x0B, 0x84, 0x23, 0xF0, 0x01, 0x02, 0x81, 0x03},
+		},
+		{
+			name: "national language",
+			h:    UDH{NewSingleShiftIE(1), NewLockingShiftIE(3)},
+			want: []byte{0x06, 0x24, 0x01, 0x01, 0x25, 0x01, 0x03},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.h.Bytes()
+			if !bytes.Equal(b, tt.want) {
+				t.Fatalf("Bytes() = %x, want %x", b, tt.want)
+			}
+			if tt.h.Len() != len(tt.want) {
+				t.Errorf("Len() = %d, want %d", tt.h.Len(), len(tt.want))
+			}
+			if len(b) == 0 {
+				return
+			}
+
+			h, rest, err := ParseUDH(tt.h.Join([]byte("payload")))
+			if err != nil {
+				t.Fatalf("ParseUDH() error = %v", err)
+			}
+			if string(rest) != "payload" {
+				t.Errorf("ParseUDH() rest = %q, want %q", rest, "payload")
+			}
+			if !bytes.Equal(h.Bytes(), tt.want) {
+				t.Errorf("ParseUDH().Bytes() = %x, want %x", h.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestUDHAccessors(t *testing.T) {
+	h := UDH{
+		NewConcatIE(0xBEEF, 4, 3, true),
+		NewPortIE(2948, 9200, true),
+		NewSingleShiftIE(2),
+		NewSpecialSMSIE(false, 0x00, 5),
+	}
+
+	ref, total, seq, ok := h.Concat()
+	if !ok || ref != 0xBEEF || total != 4 || seq != 3 {
+		t.Errorf("Concat() = %#x, %d, %d, %v", ref, total, seq, ok)
+	}
+	dst, src, ok := h.Ports()
+	if !ok || dst != 2948 || src != 9200 {
+		t.Errorf("Ports() = %d, %d, %v", dst, src, ok)
+	}
+	single, locking := h.NationalLanguage()
+	if single != 2 || locking != 0 {
+		t.Errorf("NationalLanguage() = %d, %d", single, locking)
+	}
+	special := h.SpecialSMS()
+	if len(special) != 1 || special[0].Store || special[0].Type != 0 || special[0].Count != 5 {
+		t.Errorf("SpecialSMS() = %+v", special)
+	}
+	if h.Get(IEI_PORT_8) != nil {
+		t.Errorf("Get(IEI_PORT_8) should be nil")
+	}
+
+	if _, _, _, ok := (UDH{}).Concat(); ok {
+		t.Errorf("Concat() of an empty UDH should not be ok")
+	}
+}
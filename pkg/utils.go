@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"math"
 	"strconv"
 	"time"
 	"unicode/utf8"
@@ -99,115 +98,32 @@ func GB18030ToUtf8(in string) (string, error) {
 }
 
 func GetUtf8Content(msgFmt uint8, msgContent string) (string, error) {
-	switch msgFmt {
-	case ASCII:
-		return Decode7Bit([]byte(msgContent))
-	case BINARY:
-	case UCS2:
-		return Ucs2ToUtf8(msgContent)
-	case GB18030:
-		return GB18030ToUtf8(msgContent)
-	default:
-	}
-
-	return "", errors.New("invalid msg fmt")
+	return DecodeContent(msgFmt, []byte(msgContent))
 }
 
+// Deprecated: 长短信参考号由 RefAllocator 分配, 该变量不再使用, 仅为兼容保留
 var TpUdhiSeq byte = 0x00
 
+// 按 msgFmt 拆分编码后的内容 contentBytes, 拆分后每条都带有长短信用户数据头
 func SplitLongSms(msgFmt uint8, content string, contentBytes []byte) [][]byte {
-	smsLength := 140
-	smsHeaderLength := 6
-	if msgFmt == ASCII {
-		// 7-Bit 160 个字符
-		smsLength = 160
-	}
-	smsBodyLen := smsLength - smsHeaderLength
 	var chunks [][]byte
-	num := 1
-	contentLen := len(content)
-	if (contentLen) > smsLength {
-		num = int(math.Ceil(float64(contentLen) / float64(smsBodyLen)))
-	}
-	if num == 1 {
-		chunks = append(chunks, contentBytes)
+	segments, err := DefaultSegmenter.Split("", msgFmt, nil, contentBytes)
+	if err != nil {
 		return chunks
 	}
-	tpUdhiHeader := []byte{0x05, 0x00, 0x03, TpUdhiSeq, byte(num)}
-	TpUdhiSeq++
-
-	for i := 0; i < num; i++ {
-		chunk := tpUdhiHeader
-		chunk = append(chunk, byte(i+1))
-		offset := i * smsBodyLen
-		max := offset + smsBodyLen
-		if max > contentLen {
-			max = contentLen
-		}
-
-		chunk = append(chunk, contentBytes[offset:max]...)
-		chunks = append(chunks, chunk)
+	for _, s := range segments {
+		chunks = append(chunks, s.ShortMessage())
 	}
 	return chunks
 }
 
 func GetMsgPkgs(pkg *SmppSubmitReqPkt) ([]*SmppSubmitReqPkt, error) {
-	packets := make([]*SmppSubmitReqPkt, 0)
-	contentByte := make([]byte, 0)
-	var (
-		err     error
-		content string
-	)
-
-	switch pkg.DataCoding {
-	case UCS2:
-		content, err = Utf8ToUcs2(pkg.ShortMessage)
-		if err != nil {
-			return packets, err
-		} else {
-			contentByte = []byte(content)
-		}
-	case ASCII:
-		contentByte, err = Encode7Bit(pkg.ShortMessage)
-		if err != nil {
-			content, err = Utf8ToUcs2(pkg.ShortMessage)
-			if err != nil {
-				return packets, err
-			} else {
-				contentByte = []byte(content)
-				pkg.DataCoding = UCS2
-			}
-		}
-		content = string(contentByte)
-	default:
-		return packets, fmt.Errorf("data_coding: met an unexpected data_coding [%d]", pkg.DataCoding)
-	}
-
-	chunks := SplitLongSms(pkg.DataCoding, content, contentByte)
-	var esmClass uint8
-	if len(chunks) > 1 {
-		esmClass = SM_UDH_GSM
-	}
+	return DefaultProfile.GetMsgPkgs(pkg)
+}
 
-	for _, chunk := range chunks {
-		p := &SmppSubmitReqPkt{
-			ServiceType:        pkg.ServiceType,
-			SourceAddrTON:      pkg.SourceAddrTON,
-			SourceAddrNPI:      pkg.SourceAddrNPI,
-			SourceAddr:         pkg.SourceAddr,
-			DestAddrTON:        pkg.DestAddrTON,
-			DestAddrNPI:        pkg.DestAddrNPI,
-			DestinationAddr:    pkg.DestinationAddr, // phone
-			EsmClass:           esmClass,
-			PriorityFlag:       NORMAL_PRIORITY,
-			RegisteredDelivery: NEED_REPORT,
-			DataCoding:         pkg.DataCoding,
-			SmLength:           uint8(len(chunk)),
-			ShortMessage:       string(chunk),
-		}
-		packets = append(packets, p)
-	}
-	return packets, nil
+// 编码并拆分 submit_sm, 按拆分器的拼接方式设置用户数据头或 SAR 可选参数
+func (sg *Segmenter) GetMsgPkgs(pkg *SmppSubmitReqPkt) ([]*SmppSubmitReqPkt, error) {
+	return (&Profile{Segmenter: sg}).GetMsgPkgs(pkg)
 }
 
 /*
@@ -407,3 +323,55 @@ func Decode7Bit(b []byte) (string, error) {
 	result, _, err := transform.Bytes(decoder, b)
 	return string(result), err
 }
+
+// 用户数据头长度为 udhLen 字节时, 为使 GSM 7 bit 内容从 septet 边界开始需要填充的位数
+func GSM7FillBits(udhLen int) int {
+	return (7 - udhLen*8%7) % 7
+}
+
+// 将每字节一个的 septet 压缩为 GSM 7 bit packed 格式, 内容前填充 fillBits 个 0 位
+// 按 3GPP TS 23.038 6.1.2.3.1, 最后一个字节空余 7 位时填充 CR, 以免被解码为 '@';
+// 以 CR 结尾且恰好占满最后一个字节时再补一个 CR, 以免被当作填充
+func Pack7Bit(septets []byte, fillBits int) []byte {
+	n := len(septets)
+	if bits := (fillBits + n*7) % 8; bits == 1 || (bits == 0 && n > 0 && septets[n-1] == '\r') {
+		septets = append(septets[:n:n], '\r')
+	}
+
+	packed := make([]byte, (fillBits+len(septets)*7+7)/8)
+	bit := fillBits
+	for _, s := range septets {
+		s &= 0x7F
+		i, shift := bit/8, uint(bit%8)
+		packed[i] |= s << shift
+		if shift > 1 {
+			packed[i+1] |= s >> (8 - shift)
+		}
+		bit += 7
+	}
+	return packed
+}
+
+// 将 GSM 7 bit packed 格式解压为每字节一个 septet, 跳过开头的 fillBits 个填充位
+func Unpack7Bit(packed []byte, fillBits int) []byte {
+	total := len(packed)*8 - fillBits
+	if total < 0 {
+		return nil
+	}
+
+	septets := make([]byte, 0, total/7)
+	for bit := fillBits; bit+7 <= len(packed)*8; bit += 7 {
+		i, shift := bit/8, uint(bit%8)
+		s := packed[i] >> shift
+		if shift > 1 {
+			s |= packed[i+1] << (8 - shift)
+		}
+		septets = append(septets, s&0x7F)
+	}
+
+	// 恰好占满最后一个字节的 CR 为填充
+	if n := len(septets); n > 0 && total%7 == 0 && septets[n-1] == '\r' {
+		septets = septets[:n-1]
+	}
+	return septets
+}
@@ -0,0 +1,115 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestGSM7FillBits(t *testing.T) {
+	tests := []struct {
+		udhLen int
+		want   int
+	}{
+		{0, 0},
+		{6, 1}, // 8 位参考号的长短信
+		{7, 0}, // 16 位参考号的长短信
+		{5, 2},
+		{12, 2},
+	}
+	for _, tt := range tests {
+		if got := GSM7FillBits(tt.udhLen); got != tt.want {
+			t.Errorf("GSM7FillBits(%d) = %d, want %d", tt.udhLen, got, tt.want)
+		}
+	}
+}
+
+func TestPack7Bit(t *testing.T) {
+	tests := []struct {
+		name     string
+		septets  []byte
+		fillBits int
+		want     string
+	}{
+		{"empty", nil, 0, ""},
+		{"hellohello", []byte("hellohello"), 0, "e8329bfd4697d9ec37"},
+		{"fill one bit", []byte("hi"), 1, "d069"},
+		{"seven septets pad with CR", []byte("1234567"), 0, "31d98c56b3dd1a"},
+		{"eight septets", []byte("12345678"), 0, "31d98c56b3dd70"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hex.EncodeToString(Pack7Bit(tt.septets, tt.fillBits))
+			if got != tt.want {
+				t.Errorf("Pack7Bit() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPack7BitRoundTrip(t *testing.T) {
+	texts := []string{
+		"",
+		"a",
+		"hello",
+		"1234567",
+		"12345678",
+		"The quick brown fox jumps over the lazy dog",
+		"CR\r in the middle",
+	}
+
+	for _, text := range texts {
+		for fill := 0; fill < 7; fill++ {
+			septets := []byte(text)
+			got := Unpack7Bit(Pack7Bit(septets, fill), fill)
+			if !bytes.Equal(got, septets) {
+				t.Errorf("Unpack7Bit(Pack7Bit(%q, %d)) = %q", text, fill, got)
+			}
+		}
+	}
+}
+
+func TestPack7BitTrailingCR(t *testing.T) {
+	// 以 CR 结尾且恰好占满最后一个字节时补一个 CR, 接收方会看到两个 CR
+	septets := []byte("1234567\r")
+	packed := Pack7Bit(septets, 0)
+	if len(packed) != 8 {
+		t.Fatalf("Pack7Bit() len = %d, want 8", len(packed))
+	}
+	if got := Unpack7Bit(packed, 0); string(got) != "1234567\r\r" {
+		t.Errorf("Unpack7Bit() = %q, want %q", got, "1234567\r\r")
+	}
+}
+
+func TestEncode7Bit(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    []byte
+		wantErr bool
+	}{
+		{"@", []byte{0x00}, false},
+		{"abc", []byte("abc"), false},
+		{"€", []byte{0x1B, 0x65}, false},
+		{"{}", []byte{0x1B, 0x28, 0x1B, 0x29}, false},
+		{"你好", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := Encode7Bit(tt.text)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Encode7Bit(%q) error = %v, wantErr %v", tt.text, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("Encode7Bit(%q) = %x, want %x", tt.text, got, tt.want)
+		}
+		text, err := Decode7Bit(got)
+		if err != nil || text != tt.text {
+			t.Errorf("Decode7Bit(%x) = %q, %v, want %q", got, text, err, tt.text)
+		}
+	}
+}
@@ -13,11 +13,20 @@ const (
 // DataCoding
 // 短消息内容体的编码格式
 const (
-	ASCII   = 0  // ASCII编码
-	LATIN1  = 3  // Latin 1
-	BINARY  = 4  // 二进制短消息
-	UCS2    = 8  // UCS2编码
-	GB18030 = 15 // GB18030编码
+	ASCII          = 0  // ASCII编码
+	IA5            = 1  // IA5 (CCITT T.50)/ASCII
+	OCTET          = 2  // 8 位二进制
+	LATIN1         = 3  // Latin 1
+	BINARY         = 4  // 二进制短消息
+	JIS            = 5  // JIS (X 0208-1990)
+	CYRILLIC       = 6  // Cyrillic (ISO-8859-5)
+	HEBREW         = 7  // Latin/Hebrew (ISO-8859-8)
+	UCS2           = 8  // UCS2编码
+	PICTOGRAM      = 9  // Pictogram Encoding
+	ISO2022JP      = 10 // ISO-2022-JP (Music Codes)
+	EXTENDED_KANJI = 13 // Extended Kanji JIS (X 0212-1990)
+	KSC5601        = 14 // KS C 5601
+	GB18030        = 15 // GB18030编码
 )
 
 const (
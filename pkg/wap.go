@@ -0,0 +1,187 @@
+package pkg
+
+import (
+	"strings"
+	"time"
+)
+
+// WSP 预定义的内容类型编码, 见 WAP-230-WSP Table 40
+const (
+	WSP_CONTENT_SIC                uint8 = 0x2E // application/vnd.wap.sic
+	WSP_CONTENT_SLC                uint8 = 0x30 // application/vnd.wap.slc
+	WSP_CONTENT_CONNECTIVITY_WBXML uint8 = 0x36 // application/vnd.wap.connectivity-wbxml
+	WSP_CONTENT_MMS_MESSAGE        uint8 = 0x3E // application/vnd.wap.mms-message
+)
+
+const (
+	wspPDUPush      = 0x06
+	wbxmlVersion    = 0x02 // WBXML 1.2
+	wbxmlCharset    = 0x6A // UTF-8
+	wbxmlEnd        = 0x01
+	wbxmlStrI       = 0x03
+	wbxmlOpaque     = 0xC3
+	wbxmlAttrs      = 0x80
+	wbxmlContent    = 0x40
+	wbxmlPublicSI   = 0x05
+	wbxmlPublicSL   = 0x06
+	wspCharsetUTF8  = 0xEA // Charset 参数值 106, 短整数编码
+	wspParamCharset = 0x81
+)
+
+// 无符号变长整数, 每字节 7 位, 最高位为 1 表示后面还有
+func wspUintvar(n uint32) []byte {
+	b := []byte{byte(n & 0x7F)}
+	for n >>= 7; n > 0; n >>= 7 {
+		b = append([]byte{byte(n&0x7F) | 0x80}, b...)
+	}
+	return b
+}
+
+// 长度小于 31 时为一个字节, 否则为 31 加变长整数
+func wspValueLength(n int) []byte {
+	if n < 31 {
+		return []byte{byte(n)}
+	}
+	return append([]byte{31}, wspUintvar(uint32(n))...)
+}
+
+// 无连接 WSP Push PDU, contentType 取 WSP_CONTENT_*
+// params 为已编码的内容类型参数, headers 为已编码的其他头部, 均可为 nil
+func WSPPush(tid, contentType uint8, params, headers, body []byte) []byte {
+	ct := []byte{contentType | 0x80}
+	if len(params) > 0 {
+		ct = append(wspValueLength(1+len(params)), ct...)
+		ct = append(ct, params...)
+	}
+	hdr := append(ct, headers...)
+
+	b := []byte{tid, wspPDUPush}
+	b = append(b, wspUintvar(uint32(len(hdr)))...)
+	b = append(b, hdr...)
+	return append(b, body...)
+}
+
+// Service Indication 的 action 属性
+const (
+	SI_ACTION_SIGNAL_NONE   uint8 = 0x05
+	SI_ACTION_SIGNAL_LOW    uint8 = 0x06
+	SI_ACTION_SIGNAL_MEDIUM uint8 = 0x07
+	SI_ACTION_SIGNAL_HIGH   uint8 = 0x08
+	SI_ACTION_DELETE        uint8 = 0x09
+)
+
+// Service Loading 的 action 属性
+const (
+	SL_ACTION_EXECUTE_LOW  uint8 = 0x05
+	SL_ACTION_EXECUTE_HIGH uint8 = 0x06
+	SL_ACTION_CACHE        uint8 = 0x07
+)
+
+// Service Indication (WAP-167)
+type ServiceIndication struct {
+	Href    string
+	Text    string
+	ID      string    // si-id, 为空时不携带
+	Created time.Time // 为零值时不携带
+	Expires time.Time // 为零值时不携带
+	Action  uint8     // SI_ACTION_*, 为 0 时不携带 (即 signal-medium)
+}
+
+// Service Loading (WAP-168)
+type ServiceLoading struct {
+	Href   string
+	Action uint8 // SL_ACTION_*, 为 0 时不携带 (即 execute-low)
+}
+
+func wbxmlString(s string) []byte {
+	b := append([]byte{wbxmlStrI}, s...)
+	return append(b, 0x00)
+}
+
+// href 属性, 按属性起始码表压缩常见的前缀
+func wbxmlHref(href string, prefixes []string, base uint8) []byte {
+	for i := len(prefixes) - 1; i >= 0; i-- {
+		if strings.HasPrefix(href, prefixes[i]) {
+			rest := href[len(prefixes[i]):]
+			if rest == "" {
+				return []byte{base + uint8(i)}
+			}
+			return append([]byte{base + uint8(i)}, wbxmlString(rest)...)
+		}
+	}
+	return append([]byte{base}, wbxmlString(href)...)
+}
+
+// 日期按 BCD 编码为 YYYYMMDDhhmmss 并去掉末尾为 0 的字节 (WAP-167 8.2.2)
+func wbxmlDate(t time.Time) []byte {
+	t = t.UTC()
+	bcd := func(n int) byte { return byte(n/10<<4 | n%10) }
+	d := []byte{bcd(t.Year() / 100), bcd(t.Year() % 100), bcd(int(t.Month())), bcd(t.Day()),
+		bcd(t.Hour()), bcd(t.Minute()), bcd(t.Second())}
+	for len(d) > 0 && d[len(d)-1] == 0 {
+		d = d[:len(d)-1]
+	}
+	return append([]byte{wbxmlOpaque, byte(len(d))}, d...)
+}
+
+// 编码为 WBXML
+func (si *ServiceIndication) WBXML() []byte {
+	b := []byte{wbxmlVersion, wbxmlPublicSI, wbxmlCharset, 0x00}
+	b = append(b, 0x05|wbxmlContent) // <si>
+	tag := uint8(0x06 | wbxmlAttrs)  // <indication>
+	if si.Text != "" {
+		tag |= wbxmlContent
+	}
+	b = append(b, tag)
+	b = append(b, wbxmlHref(si.Href, []string{"", "http://", "http://www.", "https://", "https://www."}, 0x0B)...)
+	if si.ID != "" {
+		b = append(b, 0x11)
+		b = append(b, wbxmlString(si.ID)...)
+	}
+	if !si.Created.IsZero() {
+		b = append(b, 0x0A)
+		b = append(b, wbxmlDate(si.Created)...)
+	}
+	if !si.Expires.IsZero() {
+		b = append(b, 0x10)
+		b = append(b, wbxmlDate(si.Expires)...)
+	}
+	if si.Action != 0 {
+		b = append(b, si.Action)
+	}
+	b = append(b, wbxmlEnd)
+	if si.Text != "" {
+		b = append(b, wbxmlString(si.Text)...)
+		b = append(b, wbxmlEnd) // </indication>
+	}
+	return append(b, wbxmlEnd) // </si>
+}
+
+// 编码为 WBXML
+func (sl *ServiceLoading) WBXML() []byte {
+	b := []byte{wbxmlVersion, wbxmlPublicSL, wbxmlCharset, 0x00}
+	b = append(b, 0x05|wbxmlAttrs) // <sl>
+	b = append(b, wbxmlHref(sl.Href, []string{"", "http://", "http://www.", "https://", "https://www."}, 0x08)...)
+	if sl.Action != 0 {
+		b = append(b, sl.Action)
+	}
+	return append(b, wbxmlEnd)
+}
+
+// 编码为 WSP Push PDU
+func (si *ServiceIndication) Push(tid uint8) []byte {
+	return WSPPush(tid, WSP_CONTENT_SIC, []byte{wspParamCharset, wspCharsetUTF8}, nil, si.WBXML())
+}
+
+func (sl *ServiceLoading) Push(tid uint8) []byte {
+	return WSPPush(tid, WSP_CONTENT_SLC, []byte{wspParamCharset, wspCharsetUTF8}, nil, sl.WBXML())
+}
+
+// 将 WSP Push PDU 拆分为发往 WAP Push 端口的 submit_sm
+func (pf *Profile) GetWAPPushPkgs(pkg *SmppSubmitReqPkt, mode PortMode, push []byte) ([]*SmppSubmitReqPkt, error) {
+	return pf.GetPortMsgPkgs(pkg, PORT_WAP_PUSH, PORT_WAP_WSP, mode, push)
+}
+
+func GetWAPPushPkgs(pkg *SmppSubmitReqPkt, mode PortMode, push []byte) ([]*SmppSubmitReqPkt, error) {
+	return DefaultProfile.GetWAPPushPkgs(pkg, mode, push)
+}
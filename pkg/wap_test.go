@@ -0,0 +1,151 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func TestWSPUintvar(t *testing.T) {
+	tests := []struct {
+		n    uint32
+		want string
+	}{
+		{0, "00"},
+		{0x7F, "7f"},
+		{0x80, "8100"},
+		{0x3FFF, "ff7f"},
+		{0x4000, "818000"},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(wspUintvar(tt.n)); got != tt.want {
+			t.Errorf("wspUintvar(%d) = %s, want %s", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestWBXML(t *testing.T) {
+	str := func(s string) []byte { return wbxmlString(s) }
+	join := func(parts ...[]byte) []byte { return bytes.Join(parts, nil) }
+
+	tests := []struct {
+		name string
+		got  []byte
+		want []byte
+	}{
+		{
+			name: "si with dates and text",
+			got: (&ServiceIndication{
+				Href:    "http://www.xyz.com/email/123/abc.wml",
+				Text:    "You have 4 new emails",
+				Created: time.Date(1999, 6, 25, 15, 23, 15, 0, time.UTC),
+				Expires: time.Date(1999, 6, 30, 0, 0, 0, 0, time.UTC),
+			}).WBXML(),
+			want: join(
+				[]byte{0x02, 0x05, 0x6A, 0x00, 0x45, 0xC6, 0x0D}, str("xyz.com/email/123/abc.wml"),
+				[]byte{0x0A, 0xC3, 0x07, 0x19, 0x99, 0x06, 0x25, 0x15, 0x23, 0x15},
+				[]byte{0x10, 0xC3, 0x04, 0x19, 0x99, 0x06, 0x30},
+				[]byte{0x01}, str("You have 4 new emails"), []byte{0x01, 0x01},
+			),
+		},
+		{
+			name: "si without text",
+			got:  (&ServiceIndication{Href: "https://a.io", ID: "1", Action: SI_ACTION_DELETE}).WBXML(),
+			want: join(
+				[]byte{0x02, 0x05, 0x6A, 0x00, 0x45, 0x86, 0x0E}, str("a.io"),
+				[]byte{0x11}, str("1"), []byte{0x09, 0x01, 0x01},
+			),
+		},
+		{
+			name: "sl",
+			got:  (&ServiceLoading{Href: "http://www.example.com/x", Action: SL_ACTION_CACHE}).WBXML(),
+			want: join([]byte{0x02, 0x06, 0x6A, 0x00, 0x85, 0x0A}, str("example.com/x"), []byte{0x07, 0x01}),
+		},
+		{
+			name: "sl with unknown scheme",
+			got:  (&ServiceLoading{Href: "ftp://a"}).WBXML(),
+			want: join([]byte{0x02, 0x06, 0x6A, 0x00, 0x85, 0x08}, str("ftp://a"), []byte{0x01}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !bytes.Equal(tt.got, tt.want) {
+				t.Errorf("WBXML() = %x, want %x", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWSPPush(t *testing.T) {
+	sl := &ServiceLoading{Href: "http://a"}
+	got := sl.Push(0x01)
+	want := append([]byte{0x01, 0x06, 0x04, 0x03, 0xB0, 0x81, 0xEA}, sl.WBXML()...)
+	if !bytes.Equal(got, want) {
+		t.Errorf("Push() = %x, want %x", got, want)
+	}
+
+	got = WSPPush(0x02, WSP_CONTENT_MMS_MESSAGE, nil, []byte{0xAF, 0x84}, []byte{0xFF})
+	want = []byte{0x02, 0x06, 0x03, 0xBE, 0xAF, 0x84, 0xFF}
+	if !bytes.Equal(got, want) {
+		t.Errorf("WSPPush() = %x, want %x", got, want)
+	}
+}
+
+func TestGetPortMsgPkgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		pf         *Profile
+		dataCoding uint8
+		mode       PortMode
+		want       uint8
+	}{
+		{"default becomes binary", &Profile{}, ASCII, PORT_UDH, BINARY},
+		{"latin1 default alphabet becomes binary", &Profile{DefaultAlphabet: LATIN1}, ASCII, PORT_UDH, BINARY},
+		{"latin1 becomes binary", &Profile{}, LATIN1, PORT_TLV, BINARY},
+		{"ucs2 becomes binary", &Profile{}, UCS2, PORT_UDH, BINARY},
+		{"octet is kept", &Profile{}, OCTET, PORT_UDH, OCTET},
+		{"gsm 8 bit class is kept", &Profile{}, 0xF5, PORT_TLV, 0xF5},
+	}
+
+	payload := make([]byte, 200)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.pf.Segmenter = &Segmenter{Refs: fixedRef(1)}
+			pkg := &SmppSubmitReqPkt{DestinationAddr: "1", DataCoding: tt.dataCoding}
+			packets, err := tt.pf.GetPortMsgPkgs(pkg, PORT_WAP_PUSH, PORT_WAP_WSP, tt.mode, payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pkg.DataCoding != tt.dataCoding {
+				t.Errorf("caller's data_coding changed to %d", pkg.DataCoding)
+			}
+			if len(packets) != 2 {
+				t.Fatalf("got %d packets, want 2", len(packets))
+			}
+
+			var joined []byte
+			for _, p := range packets {
+				if p.DataCoding != tt.want {
+					t.Errorf("data_coding = %#x, want %#x", p.DataCoding, tt.want)
+				}
+				h, ud, err := splitUDH(p.EsmClass, p.ShortMessage)
+				if err != nil {
+					t.Fatal(err)
+				}
+				dst, src, ok := h.Ports()
+				if tt.mode == PORT_TLV {
+					dst, src, ok = optionPorts(p.Options)
+				}
+				if !ok || dst != PORT_WAP_PUSH || src != PORT_WAP_WSP {
+					t.Errorf("ports = %d, %d, %v", dst, src, ok)
+				}
+				joined = append(joined, ud...)
+			}
+			if !bytes.Equal(joined, payload) {
+				t.Errorf("joined payload differs")
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package server
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
@@ -25,6 +27,15 @@ type Packet struct {
 	*pkg.Conn
 }
 
+// 对端的 TLS 客户端证书, 用于识别客户端身份; 不是 TLS 连接或对端未提供证书时返回 nil
+func (p *Packet) PeerCertificate() *x509.Certificate {
+	state, ok := p.Conn.TLSConnectionState()
+	if !ok || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+	return state.PeerCertificates[0]
+}
+
 type Response struct {
 	*Packet
 	pkg.Packer
@@ -50,10 +61,16 @@ type Server struct {
 	ReadTimeout time.Duration
 	T           time.Duration
 	N           int32
+	Profile     *pkg.Profile // 对端的编码习惯, 为 nil 时使用 pkg.DefaultProfile
+
+	// 不为 nil 时 ListenAndServe 使用 TLS, 需要双向认证时设置 ClientAuth 与 ClientCAs
+	TLSConfig *tls.Config
 
 	ErrorLog *log.Logger
 }
 
+const tlsHandshakeTimeout = 10 * time.Second
+
 type conn struct {
 	*pkg.Conn
 	server      *Server
@@ -65,6 +82,8 @@ type conn struct {
 	done    chan struct{}
 	exceed  chan struct{}
 	counter int32
+
+	unbound bool // 对端已发送 unbind, 关闭时不再发送 unbind
 }
 
 func (srv *Server) Serve(l net.Listener) error {
@@ -237,6 +256,54 @@ func (c *conn) readPacket() (*Response, error) {
 		c.server.ErrorLog.Printf("receive a smpp query response from %v[%d]\n",
 			c.Conn.RemoteAddr(), p.SequenceNum)
 
+	case *pkg.SmppCancelReqPkt:
+		rsp = &Response{
+			Packet: &Packet{
+				Packer: p,
+				Conn:   c.Conn,
+			},
+			Packer: &pkg.SmppCancelRespPkt{
+				SequenceNum: p.SequenceNum,
+			},
+			SequenceNum: p.SequenceNum,
+		}
+		c.server.ErrorLog.Printf("receive a smpp cancel request from %v[%d]\n",
+			c.Conn.RemoteAddr(), p.SequenceNum)
+
+	case *pkg.SmppCancelRespPkt:
+		rsp = &Response{
+			Packet: &Packet{
+				Packer: p,
+				Conn:   c.Conn,
+			},
+		}
+		c.server.ErrorLog.Printf("receive a smpp cancel response from %v[%d]\n",
+			c.Conn.RemoteAddr(), p.SequenceNum)
+
+	case *pkg.SmppDataReqPkt:
+		rsp = &Response{
+			Packet: &Packet{
+				Packer: p,
+				Conn:   c.Conn,
+			},
+			Packer: &pkg.SmppDataRespPkt{
+				SequenceNum: p.SequenceNum,
+			},
+			SequenceNum: p.SequenceNum,
+		}
+		c.server.ErrorLog.Printf("receive a smpp data request from %v[%d]\n",
+			c.Conn.RemoteAddr(), p.SequenceNum)
+
+	case *pkg.SmppDataRespPkt:
+		rsp = &Response{
+			Packet: &Packet{
+				Packer: p,
+				Conn:   c.Conn,
+			},
+		}
+		c.server.ErrorLog.Printf("receive a smpp data response from %v[%d]\n",
+			c.Conn.RemoteAddr(), p.SequenceNum)
+
 	default:
 		return nil, pkg.NewOpError(ErrUnsupportedPkt,
 			fmt.Sprintf("readPacket: receive unsupported packet type: %#v", p))
@@ -245,11 +312,13 @@ func (c *conn) readPacket() (*Response, error) {
 }
 
 func (c *conn) close() {
-	p := &pkg.SmppUnbindReqPkt{}
+	if !c.unbound {
+		p := &pkg.SmppUnbindReqPkt{}
 
-	err := c.Conn.SendPkt(p, <-c.Conn.SequenceNum)
-	if err != nil {
-		c.server.ErrorLog.Printf("send smpp exit request packet to %v error: %v\n", c.Conn.RemoteAddr(), err)
+		err := c.Conn.SendPkt(p, <-c.Conn.SequenceNum)
+		if err != nil {
+			c.server.ErrorLog.Printf("send smpp exit request packet to %v error: %v\n", c.Conn.RemoteAddr(), err)
+		}
 	}
 
 	close(c.done)
@@ -312,6 +381,17 @@ func (c *conn) serve() {
 		}
 	}()
 
+	// 先完成 TLS 握手, 处理 bind 时即可获取客户端证书
+	if tc, ok := c.Conn.Conn.(*tls.Conn); ok {
+		tc.SetDeadline(time.Now().Add(tlsHandshakeTimeout))
+		if err := tc.Handshake(); err != nil {
+			c.server.ErrorLog.Printf("tls handshake with %v error: %v\n", c.Conn.RemoteAddr(), err)
+			c.Conn.Close()
+			return
+		}
+		tc.SetDeadline(time.Time{})
+	}
+
 	defer c.close()
 
 	startActiveTest(c)
@@ -339,14 +419,33 @@ func (c *conn) serve() {
 		if err != nil {
 			break
 		}
+
+		// 之前的请求均已响应, 回复 unbind_resp 后关闭连接
+		if _, ok := r.Packet.Packer.(*pkg.SmppUnbindReqPkt); ok {
+			c.unbound = true
+			break
+		}
 	}
 }
 
+// 处理一个已建立的连接直到断开, 如 net.Pipe 的一端或自行 Accept 的连接
+func (srv *Server) ServeConn(rwc net.Conn) {
+	c, err := srv.newConn(rwc)
+	if err != nil {
+		rwc.Close()
+		return
+	}
+	c.serve()
+}
+
 func (srv *Server) newConn(rwc net.Conn) (c *conn, err error) {
 	c = new(conn)
 	c.server = srv
 	c.readTimeout = c.server.ReadTimeout
 	c.Conn = pkg.NewConnection(rwc, srv.Version)
+	if srv.Profile != nil {
+		c.Conn.Profile = srv.Profile
+	}
 	c.Conn.SetState(pkg.CONNECTION_CONNECTED)
 	c.n = c.server.N
 	c.t = c.server.T
@@ -361,7 +460,35 @@ func (srv *Server) listenAndServe() error {
 	if err != nil {
 		return err
 	}
-	return srv.Serve(tcpKeepAliveListener{ln.(*net.TCPListener)})
+	var l net.Listener = tcpKeepAliveListener{ln.(*net.TCPListener)}
+	if srv.TLSConfig != nil {
+		l = tls.NewListener(l, srv.TLSConfig)
+	}
+	return srv.Serve(l)
+}
+
+// 监听 srv.Addr 并处理连接, 需要设置 Profile 等字段时使用
+func (srv *Server) ListenAndServe() error {
+	return srv.listenAndServe()
+}
+
+// 同 ListenAndServe, 使用 TLS, 证书与私钥从文件读取并加入 TLSConfig 的副本
+func (srv *Server) ListenAndServeTLS(certFile, keyFile string) error {
+	var config *tls.Config
+	if srv.TLSConfig != nil {
+		config = srv.TLSConfig.Clone()
+	} else {
+		config = &tls.Config{}
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+	config.Certificates = append(config.Certificates, cert)
+
+	s := *srv
+	s.TLSConfig = config
+	return s.listenAndServe()
 }
 
 func ListenAndServe(addr string, version uint8, t, readTimeout time.Duration, n int32, logWriter io.Writer, handlers ...Handler) error {
@@ -0,0 +1,223 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/boxtsecond/gosmpp/client"
+	"github.com/boxtsecond/gosmpp/pkg"
+)
+
+var discardLog = log.New(ioutil.Discard, "", 0)
+
+// 登录总是成功, 其他请求以默认响应回复
+func acceptBind(r *Response, p *Packet) (bool, error) {
+	if req, ok := p.Packer.(*pkg.SmppBindTransceiverReqPkt); ok {
+		resp := r.Packer.(*pkg.SmppBindTransceiverRespPkt)
+		resp.SystemID = req.SystemID
+		resp.ScInterfaceVersion = pkg.NewTLV(pkg.TAG_SCInterfaceVersion, []byte{pkg.VERSION})
+	}
+	return false, nil
+}
+
+func newTestServer(h HandlerFunc) *Server {
+	return &Server{
+		Handler:  h,
+		Version:  pkg.VERSION,
+		T:        time.Minute,
+		N:        3,
+		ErrorLog: discardLog,
+	}
+}
+
+// 测试用的证书, 由 ca 签发; ca 为 nil 时自签名
+func testCert(t *testing.T, cn string, ca *tls.Certificate) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     []string{cn},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	parent, signer := tmpl, interface{}(key)
+	if ca == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+	} else {
+		parent, signer = ca.Leaf, ca.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// 本机的一个空闲端口
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func TestListenAndServeTLS(t *testing.T) {
+	ca := testCert(t, "test ca", nil)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+	serverCert := testCert(t, "127.0.0.1", &ca)
+	clientCert := testCert(t, "esme", &ca)
+
+	peers := make(chan string, 1)
+	srv := newTestServer(func(r *Response, p *Packet) (bool, error) {
+		if _, ok := p.Packer.(*pkg.SmppBindTransceiverReqPkt); ok {
+			cn := ""
+			if cert := p.PeerCertificate(); cert != nil {
+				cn = cert.Subject.CommonName
+			}
+			peers <- cn
+		}
+		return acceptBind(r, p)
+	})
+	srv.Addr = freeAddr(t)
+	srv.TLSConfig = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	}
+	go srv.ListenAndServe()
+
+	tests := []struct {
+		name    string
+		config  *tls.Config
+		wantErr bool
+	}{
+		{"client certificate", &tls.Config{RootCAs: pool, Certificates: []tls.Certificate{clientCert}}, false},
+		{"no client certificate", &tls.Config{RootCAs: pool}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := client.NewClient(pkg.VERSION, client.WithTLS(tt.config), client.WithErrorLog(discardLog))
+			var err error
+			for i := 0; i < 50; i++ { // 等待开始监听
+				if err = cli.Connect(srv.Addr, "id", "pw", "", 0, 0, "", time.Second); err == nil || tt.wantErr {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Connect() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer cli.Disconnect()
+
+			select {
+			case cn := <-peers:
+				if cn != "esme" {
+					t.Errorf("PeerCertificate() CN = %q, want %q", cn, "esme")
+				}
+			case <-time.After(time.Second):
+				t.Fatal("bind was not handled")
+			}
+		})
+	}
+}
+
+func TestServeConnUnbind(t *testing.T) {
+	tests := []struct {
+		name string
+		req  pkg.Packer
+		want []pkg.CommandID // 依次收到的 PDU, 之后连接关闭
+	}{
+		{"peer unbind", &pkg.SmppUnbindReqPkt{}, []pkg.CommandID{pkg.SMPP_UNBIND_RESP}},
+		{"handler error", &pkg.SmppQueryReqPkt{MsgID: "1"}, []pkg.CommandID{pkg.SMPP_QUERY_RESP, pkg.SMPP_UNBIND}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer(func(r *Response, p *Packet) (bool, error) {
+				if _, ok := p.Packer.(*pkg.SmppQueryReqPkt); ok {
+					return false, ErrUnsupportedPkt
+				}
+				return false, nil
+			})
+			client, server := net.Pipe()
+			done := make(chan struct{})
+			go func() {
+				srv.ServeConn(server)
+				close(done)
+			}()
+
+			c := pkg.NewConnection(client, pkg.VERSION)
+			c.SetState(pkg.CONNECTION_CONNECTED)
+			defer c.Close()
+			if err := c.SendPkt(tt.req, 5); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, want := range tt.want {
+				p, err := c.RecvAndUnpackPkt(time.Second)
+				if err != nil {
+					t.Fatalf("RecvAndUnpackPkt() error = %v, want %s", err, want)
+				}
+				if got := commandID(p); got != want {
+					t.Fatalf("got %s, want %s", got, want)
+				}
+				if want == pkg.SMPP_UNBIND_RESP {
+					if r := p.(*pkg.SmppUnbindRespPkt); r.SequenceNum != 5 {
+						t.Errorf("unbind_resp sequence = %d, want 5", r.SequenceNum)
+					}
+				}
+			}
+			if p, err := c.RecvAndUnpackPkt(time.Second); err == nil {
+				t.Errorf("got %s after the expected PDUs, want the connection closed", commandID(p))
+			}
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("ServeConn() did not return")
+			}
+		})
+	}
+}
+
+func commandID(p interface{}) pkg.CommandID {
+	switch p.(type) {
+	case *pkg.SmppUnbindReqPkt:
+		return pkg.SMPP_UNBIND
+	case *pkg.SmppUnbindRespPkt:
+		return pkg.SMPP_UNBIND_RESP
+	case *pkg.SmppQueryRespPkt:
+		return pkg.SMPP_QUERY_RESP
+	case *pkg.SmppEnquireLinkReqPkt:
+		return pkg.SMPP_ENQUIRE_LINK
+	}
+	return 0
+}